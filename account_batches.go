@@ -1,16 +1,12 @@
 package esendex
 
-import "net/http"
-
 // Batches returns a list of batches sent by the account.
 func (c *AccountClient) Batches(opts ...Option) (*BatchesResponse, error) {
-	accountOption := func(r *http.Request) {
-		q := r.URL.Query()
-
-		q.Add("filterBy", "account")
-		q.Add("filterValue", c.reference)
-
-		r.URL.RawQuery = q.Encode()
+	accountOption := func(p *Params) error {
+		if err := p.Set("filterBy", "account"); err != nil {
+			return err
+		}
+		return p.Set("filterValue", c.reference)
 	}
 
 	return c.Client.Batches(append(opts, accountOption)...)
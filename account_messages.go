@@ -1,17 +1,9 @@
 package esendex
 
-import (
-	"net/http"
-)
-
 // Sent returns a list of messages sent by the account.
 func (c *AccountClient) Sent(opts ...Option) (*SentMessagesResponse, error) {
-	accountOption := func(r *http.Request) {
-		q := r.URL.Query()
-
-		q.Add("accountReference", c.reference)
-
-		r.URL.RawQuery = q.Encode()
+	accountOption := func(p *Params) error {
+		return p.Set("accountReference", c.reference)
 	}
 
 	return c.Client.Sent(append(opts, accountOption)...)
@@ -24,8 +16,8 @@ func (c *AccountClient) Received(opts ...Option) (*ReceivedMessagesResponse, err
 		return nil, err
 	}
 
-	for _, opt := range opts {
-		opt(req)
+	if err := applyOptions(req, opts); err != nil {
+		return nil, err
 	}
 
 	var v inboxResponse
@@ -48,7 +40,7 @@ func (c *AccountClient) Received(opts ...Option) (*ReceivedMessagesResponse, err
 			URI:        message.URI,
 			Reference:  message.Reference,
 			Status:     message.Status,
-			ReceivedAt: message.ReceivedAt.Time,
+			ReceivedAt: c.in(message.ReceivedAt.Time),
 			Type:       MessageType(message.Type),
 			To:         message.To,
 			From:       message.From,
@@ -56,7 +48,7 @@ func (c *AccountClient) Received(opts ...Option) (*ReceivedMessagesResponse, err
 			bodyURI:    message.Body.URI,
 			Direction:  message.Direction,
 			Parts:      message.Parts,
-			ReadAt:     message.ReadAt.Time,
+			ReadAt:     c.in(message.ReadAt.Time),
 			ReadBy:     message.ReadBy,
 		}
 	}
@@ -2,9 +2,14 @@ package esendex
 
 import (
 	"encoding/xml"
+	"errors"
 	"time"
 )
 
+// ErrAccountNotFound is returned by AccountByReference when none of the accounts
+// available to the user have the given reference.
+var ErrAccountNotFound = errors.New("esendex: account not found")
+
 // Accounts returns a list of accounts the user is on.
 func (c *Client) Accounts() (*AccountsResponse, error) {
 	req, err := c.newRequest("GET", "/v1.0/accounts", nil)
@@ -30,7 +35,7 @@ func (c *Client) Accounts() (*AccountsResponse, error) {
 			Address:           account.Address,
 			Type:              account.Type,
 			MessagesRemaining: account.MessagesRemaining,
-			ExpiresOn:         account.ExpiresOn.Time,
+			ExpiresOn:         c.in(account.ExpiresOn.Time),
 			Role:              account.Role,
 			SettingsURI:       account.Settings.URI,
 		}
@@ -39,6 +44,52 @@ func (c *Client) Accounts() (*AccountsResponse, error) {
 	return response, nil
 }
 
+// AccountByID returns the account with the given id.
+func (c *Client) AccountByID(id string) (*AccountResponse, error) {
+	req, err := c.newRequest("GET", "/v1.0/accounts/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var v accountsResponseAccount
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &AccountResponse{
+		ID:                v.ID,
+		URI:               v.URI,
+		Reference:         v.Reference,
+		Label:             v.Label,
+		Address:           v.Address,
+		Type:              v.Type,
+		MessagesRemaining: v.MessagesRemaining,
+		ExpiresOn:         c.in(v.ExpiresOn.Time),
+		Role:              v.Role,
+		SettingsURI:       v.Settings.URI,
+	}
+
+	return response, nil
+}
+
+// AccountByReference returns the account with the given reference. The API does
+// not allow accounts to be fetched by reference directly, so this lists the
+// accounts available to the user and returns ErrAccountNotFound if none match.
+func (c *Client) AccountByReference(reference string) (*AccountResponse, error) {
+	resp, err := c.Accounts()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, account := range resp.Accounts {
+		if account.Reference == reference {
+			return &account, nil
+		}
+	}
+
+	return nil, ErrAccountNotFound
+}
+
 // AccountsResponse is a list of accounts.
 type AccountsResponse struct {
 	Accounts []AccountResponse
@@ -85,11 +136,11 @@ type accountsTime struct {
 }
 
 func (t accountsTime) MarshalText() ([]byte, error) {
-	return []byte(t.Format(accountsTimeFormat)), nil
+	return []byte(formatTime(t.Time, accountsTimeFormat)), nil
 }
 
 func (t *accountsTime) UnmarshalText(data []byte) error {
-	g, err := time.ParseInLocation(accountsTimeFormat, string(data), time.UTC)
+	g, err := parseTime(string(data))
 	if err != nil {
 		return err
 	}
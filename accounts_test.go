@@ -76,3 +76,95 @@ func TestAccounts(t *testing.T) {
 		assert.Equal(settingsURI, account.SettingsURI)
 	}
 }
+
+func TestAccountByID(t *testing.T) {
+	const (
+		id                = "accountid"
+		uri               = "http://someaccount"
+		reference         = "EX093052"
+		label             = "My account"
+		address           = "443523"
+		accountType       = "Professional"
+		messagesRemaining = 2322
+		role              = "CoolUser"
+		settingsURI       = "http://somesettings"
+	)
+
+	var (
+		expiresOn    = time.Date(2012, 1, 1, 12, 0, 5, 0, time.UTC)
+		expiresOnStr = "2012-01-01T12:00:05"
+	)
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<account id="`+id+`" uri="`+uri+`" xmlns="http://api.esendex.com/ns/">
+ <reference>`+reference+`</reference>
+ <label>`+label+`</label>
+ <address>`+address+`</address>
+ <type>`+accountType+`</type>
+ <messagesremaining>`+strconv.Itoa(messagesRemaining)+`</messagesremaining>
+ <expireson>`+expiresOnStr+`</expireson>
+ <role>`+role+`</role>
+ <settings uri="`+settingsURI+`" />
+</account>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	account, err := client.AccountByID(id)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/accounts/"+id, h.Request.URL.String())
+
+	if user, pass, ok := h.Request.BasicAuth(); assert.True(ok) {
+		assert.Equal("user", user)
+		assert.Equal("pass", pass)
+	}
+
+	assert.Equal(id, account.ID)
+	assert.Equal(uri, account.URI)
+	assert.Equal(reference, account.Reference)
+	assert.Equal(label, account.Label)
+	assert.Equal(address, account.Address)
+	assert.Equal(accountType, account.Type)
+	assert.Equal(messagesRemaining, account.MessagesRemaining)
+	assert.Equal(expiresOn, account.ExpiresOn)
+	assert.Equal(role, account.Role)
+	assert.Equal(settingsURI, account.SettingsURI)
+}
+
+func TestAccountByReference(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<accounts xmlns="http://api.esendex.com/ns/">
+ <account id="first" uri="http://first">
+  <reference>EX000001</reference>
+ </account>
+ <account id="second" uri="http://second">
+  <reference>EX000002</reference>
+ </account>
+</accounts>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	assert := assert.New(t)
+
+	account, err := client.AccountByReference("EX000002")
+	if assert.Nil(err) {
+		assert.Equal("second", account.ID)
+		assert.Equal("EX000002", account.Reference)
+	}
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/accounts", h.Request.URL.String())
+
+	_, err = client.AccountByReference("EX000003")
+	assert.Equal(ErrAccountNotFound, err)
+}
@@ -0,0 +1,57 @@
+package esendex
+
+import "time"
+
+// MessageService is the set of operations for reading sent and received
+// messages.
+type MessageService interface {
+	Sent(opts ...Option) (*SentMessagesResponse, error)
+	Received(opts ...Option) (*ReceivedMessagesResponse, error)
+	Message(id string) (*MessageResponse, error)
+	Body(message MessageWithBody) (*MessageBody, error)
+	MarkRead(id string) error
+	MarkUnread(id string) error
+}
+
+// BatchService is the set of operations for reading and cancelling message
+// batches.
+type BatchService interface {
+	Batches(opts ...Option) (*BatchesResponse, error)
+	Batch(id string) (*BatchResponse, error)
+	CancelBatch(id string) error
+}
+
+// AccountsService is the set of operations for reading the accounts available
+// to the user.
+type AccountsService interface {
+	Accounts() (*AccountsResponse, error)
+	AccountByID(id string) (*AccountResponse, error)
+	AccountByReference(reference string) (*AccountResponse, error)
+}
+
+// SendService is the set of operations for dispatching messages from an
+// account.
+type SendService interface {
+	Send(messages []Message) (*SendResponse, error)
+	SendFrom(from string, messages []Message) (*SendResponse, error)
+	SendAt(sendAt time.Time, messages []Message) (*SendResponse, error)
+}
+
+// API describes the operations of a Client. It allows code using a Client to
+// be given a fake in tests.
+type API interface {
+	MessageService
+	BatchService
+	AccountsService
+}
+
+// AccountAPI describes the operations of an AccountClient.
+type AccountAPI interface {
+	API
+	SendService
+}
+
+var (
+	_ API        = (*Client)(nil)
+	_ AccountAPI = (*AccountClient)(nil)
+)
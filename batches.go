@@ -32,8 +32,8 @@ func (c *Client) Batches(opts ...Option) (*BatchesResponse, error) {
 		return nil, err
 	}
 
-	for _, opt := range opts {
-		opt(req)
+	if err := applyOptions(req, opts); err != nil {
+		return nil, err
 	}
 
 	var v messageBatchesResponse
@@ -62,7 +62,7 @@ func (c *Client) Batches(opts ...Option) (*BatchesResponse, error) {
 		response.Batches[i] = BatchResponse{
 			ID:                 batch.ID,
 			URI:                batch.URI,
-			CreatedAt:          batch.CreatedAt,
+			CreatedAt:          c.in(batch.CreatedAt.Time),
 			BatchSize:          batch.BatchSize,
 			PersistedBatchSize: batch.PersistedBatchSize,
 			Status:             status,
@@ -98,7 +98,7 @@ func (c *Client) Batch(id string) (*BatchResponse, error) {
 	response := &BatchResponse{
 		ID:                 v.ID,
 		URI:                v.URI,
-		CreatedAt:          v.CreatedAt,
+		CreatedAt:          c.in(v.CreatedAt.Time),
 		BatchSize:          v.BatchSize,
 		PersistedBatchSize: v.PersistedBatchSize,
 		Status:             status,
@@ -132,7 +132,7 @@ type messageBatchesResponse struct {
 type messageBatchResponse struct {
 	ID                 string                       `xml:"id,attr"`
 	URI                string                       `xml:"uri,attr"`
-	CreatedAt          time.Time                    `xml:"createdat"`
+	CreatedAt          messageHeaderTime            `xml:"createdat"`
 	BatchSize          int                          `xml:"batchsize"`
 	PersistedBatchSize int                          `xml:"persistedbatchsize"`
 	Status             messageBatchResponseStatuses `xml:"status"`
@@ -3,10 +3,13 @@ package esendex
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/xml"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const (
@@ -16,12 +19,44 @@ const (
 
 // Client is the entry point for accessing the Esendex REST API.
 type Client struct {
-	client *http.Client
-	user   string
-	pass   string
+	user    string
+	pass    string
+	session string
 
 	BaseURL   *url.URL
 	UserAgent string
+
+	// HTTPClient is used to make requests to the API, it defaults to
+	// http.DefaultClient. To capture or replay API conversations replace it
+	// with a client using a Recorder or Replayer, such as
+	// &http.Client{Transport: rec}, rather than changing the Transport of the
+	// default, which is shared by every user of net/http in the program.
+	HTTPClient *http.Client
+
+	// Logger, if set, is used to log each request made to the API. What is
+	// logged is controlled by LogOptions.
+	Logger     *slog.Logger
+	LogOptions LogOptions
+
+	// Metrics, if set, is given measurements of each request and of the
+	// messages sent.
+	Metrics Metrics
+
+	// Tracer, if set, is used to start a span around each request, and around
+	// each send.
+	Tracer Tracer
+
+	// Middleware wraps each request made to the API, the first being the
+	// outermost.
+	Middleware []Middleware
+
+	// Sandbox, if set, prevents messages being sent to numbers it does not
+	// allow.
+	Sandbox *Sandbox
+
+	// Location, if set, is the location that times in responses are returned
+	// in. Otherwise they are in UTC.
+	Location *time.Location
 }
 
 // New returns a new API client that authenticates with the credentials provided.
@@ -29,12 +64,12 @@ func New(user, pass string) *Client {
 	baseURL, _ := url.Parse(defaultBaseURL)
 
 	return &Client{
-		client: http.DefaultClient,
-		user:   user,
-		pass:   pass,
+		user: user,
+		pass: pass,
 
-		BaseURL:   baseURL,
-		UserAgent: defaultUserAgent,
+		BaseURL:    baseURL,
+		UserAgent:  defaultUserAgent,
+		HTTPClient: http.DefaultClient,
 	}
 }
 
@@ -58,35 +93,62 @@ func (c *Client) newRequest(method, path string, body interface{}) (*http.Reques
 
 	req.Header.Add("Content-Type", "application/xml")
 	req.Header.Add("User-Agent", c.UserAgent)
-	req.SetBasicAuth(c.user, c.pass)
+	if c.session != "" {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.session)))
+	} else {
+		req.SetBasicAuth(c.user, c.pass)
+	}
 
 	return req, nil
 }
 
 func (c *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.client.Do(req)
+	return c.doCall(&Call{Request: req}, v)
+}
+
+// doCall makes the call through the Client's Middleware, decoding the response
+// into v.
+func (c *Client) doCall(call *Call, v interface{}) (*http.Response, error) {
+	endpoint := endpointName(call.Request.URL.Path)
+
+	ctx, span := c.startSpan(call.Request.Context(), call.Request.Method+" "+endpoint)
+	defer span.End()
+
+	call.Request = call.Request.WithContext(ctx)
+	call.target = v
+
+	span.SetAttribute("http.request.method", call.Request.Method)
+	span.SetAttribute("server.address", call.Request.URL.Hostname())
+	span.SetAttribute("url.path", call.Request.URL.Path)
+
+	resp, err := c.handler()(call)
+	if resp != nil {
+		span.SetAttribute("http.response.status_code", resp.StatusCode)
+	}
 	if err != nil {
-		return resp, err
+		span.RecordError(err)
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, ClientError{
-			Method: req.Method,
-			Path:   req.URL.Path,
-			Code:   resp.StatusCode,
-		}
-	}
+	return resp, err
+}
 
-	defer func() {
-		if rerr := resp.Body.Close(); err == nil {
-			err = rerr
-		}
-	}()
+// roundTrip sends the request to the API, logging and measuring it.
+func (c *Client) roundTrip(call *Call) (*http.Response, error) {
+	req := call.Request
 
-	if v != nil {
-		if err := xml.NewDecoder(resp.Body).Decode(v); err != nil {
-			return resp, err
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	duration := time.Since(start)
+
+	c.logRequest(req, resp, duration, err)
+
+	if c.Metrics != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
 		}
+
+		c.Metrics.RequestCompleted(req.Method, endpointName(req.URL.Path), status, duration)
 	}
 
 	return resp, err
@@ -96,6 +158,10 @@ func (c *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
 type AccountClient struct {
 	*Client
 	reference string
+
+	// OptOutFilter, if set, is used to check recipients against the account's
+	// opt-outs before messages are sent.
+	OptOutFilter *OptOutFilter
 }
 
 // Account creates a client that can make requests scoped to a specific account
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+	"hawx.me/code/hadfield"
+)
+
+// finalBatchStatuses are the statuses a message in a batch will not move on
+// from.
+var finalBatchStatuses = []string{
+	"authorisationfailed",
+	"cancelled",
+	"delivered",
+	"failed",
+	"rejected",
+	"validityperiodexpired",
+}
+
+func batchesCmd(client *esendex.Client) *hadfield.Command {
+	var page int
+
+	cmd := &hadfield.Command{
+		Usage: "batches [options]",
+		Short: "lists sent batches",
+		Long: `
+  Batches displays a list of sent message batches. If --account-reference is
+  given only batches sent by that account are listed.
+
+    --page NUM       # Display given page
+`,
+		Run: func(cmd *hadfield.Command, args []string) {
+			var (
+				resp *esendex.BatchesResponse
+				err  error
+			)
+
+			if *accountReference != "" {
+				resp, err = client.Account(*accountReference).Batches(pageOpts(page))
+			} else {
+				resp, err = client.Batches(pageOpts(page))
+			}
+
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			printOutput(resp.Batches)
+		},
+	}
+
+	cmd.Flag.IntVar(&page, "page", 1, "")
+
+	return cmd
+}
+
+func batchCmd(client *esendex.Client) *hadfield.Command {
+	var interval time.Duration
+
+	cmd := &hadfield.Command{
+		Usage: "batch [options] [cancel|watch] BATCHID",
+		Short: "displays, cancels or watches a batch",
+		Long: `
+  Batch displays the details for a batch.
+
+  Batch cancel prevents a scheduled batch from being sent.
+
+  Batch watch displays the status counts for a batch each time they change,
+  until every message in the batch has finished. With an --output other than
+  table the whole batch is written on each change.
+
+    --interval DUR   # Time to wait between checks when watching (default: 5s)
+`,
+		Run: func(cmd *hadfield.Command, args []string) {
+			args = parseInterspersed(cmd, args)
+
+			if len(args) < 1 {
+				log.Fatal("Require BATCHID parameter")
+			}
+
+			switch args[0] {
+			case "cancel":
+				if len(args) < 2 {
+					log.Fatal("Require BATCHID parameter")
+				}
+
+				if err := client.CancelBatch(args[1]); err != nil {
+					log.Fatal(err)
+				}
+
+				fmt.Fprintf(os.Stderr, "Cancelled %s\n", args[1])
+
+			case "watch":
+				if len(args) < 2 {
+					log.Fatal("Require BATCHID parameter")
+				}
+
+				if err := watchBatch(client, args[1], interval); err != nil {
+					log.Fatal(err)
+				}
+
+			default:
+				resp, err := client.Batch(args[0])
+				if err != nil {
+					log.Fatal(err)
+				}
+
+				printOutput(resp)
+			}
+		},
+	}
+
+	cmd.Flag.DurationVar(&interval, "interval", 5*time.Second, "")
+
+	return cmd
+}
+
+func watchBatch(client *esendex.Client, id string, interval time.Duration) error {
+	last := ""
+
+	for {
+		batch, err := client.Batch(id)
+		if err != nil {
+			return err
+		}
+
+		if line := formatBatchStatus(batch.Status); line != last {
+			if *output == "table" {
+				fmt.Printf("%s  %s\n", time.Now().Format("15:04:05"), line)
+			} else {
+				printOutput(batch)
+			}
+			last = line
+		}
+
+		if batchFinished(batch) {
+			return nil
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+func formatBatchStatus(status map[string]int) string {
+	names := make([]string, 0, len(status))
+	for name := range status {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%d", name, status[name])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func batchFinished(batch *esendex.BatchResponse) bool {
+	finished := 0
+	for _, name := range finalBatchStatuses {
+		finished += batch.Status[name]
+	}
+
+	return finished >= batch.BatchSize
+}
@@ -3,9 +3,10 @@ package main
 import (
 	"flag"
 	"log"
+	"os"
+	"time"
 
 	"github.com/esendex/esendex-go-sdk"
-	"github.com/gobs/pretty"
 	"hawx.me/code/hadfield"
 )
 
@@ -13,6 +14,9 @@ var (
 	accountReference = flag.String("account-reference", "", "")
 	username         = flag.String("username", "", "")
 	password         = flag.String("password", "", "")
+	profileName      = flag.String("profile", "", "")
+	baseURL          = flag.String("base-url", "", "")
+	output           = flag.String("output", "table", "")
 )
 
 const pageSize = 20
@@ -23,15 +27,72 @@ func pageOpts(page int) esendex.Option {
 	return esendex.Page(startIndex, pageSize)
 }
 
+// parseInterspersed parses the flags in args, which may follow the positional
+// arguments as in "send-csv FILE --template TEXT", and returns the positional
+// arguments. The flag package stops at the first positional argument, so
+// without this any later flags would be silently ignored.
+func parseInterspersed(cmd *hadfield.Command, args []string) []string {
+	var positional []string
+
+	for len(args) > 0 {
+		if args[0] == "--" {
+			return append(positional, args[1:]...)
+		}
+
+		if len(args[0]) < 2 || args[0][0] != '-' {
+			positional = append(positional, args[0])
+			args = args[1:]
+			continue
+		}
+
+		if err := cmd.Flag.Parse(args); err != nil {
+			os.Exit(2)
+		}
+
+		rest := cmd.Flag.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...)
+		}
+		args = rest
+	}
+
+	return positional
+}
+
 var templates = hadfield.Templates{
 	Help: `usage: esendex [command] [arguments]
 
   A command line client for the Esendex REST API.
 
   Options:
-    --username USER    # Username to authenticate with
-    --password PASS    # Password to authenticate with
-    --help             # Display this message
+    --profile NAME              # Profile to use from the config file
+    --username USER             # Username to authenticate with
+    --password PASS             # Password to authenticate with
+    --account-reference REF     # Account to use for account commands
+    --base-url URL              # Base URL of the API
+    --output FORMAT             # One of table, json, ndjson, csv or
+                                #   template=TEXT (default: table)
+    --help                      # Display this message
+
+  Options not given are taken from the environment variables ESENDEX_PROFILE,
+  ESENDEX_USERNAME, ESENDEX_PASSWORD, ESENDEX_SESSION,
+  ESENDEX_ACCOUNT_REFERENCE and ESENDEX_BASE_URL, then from the profile. The
+  config file is read from ESENDEX_CONFIG, or esendex/config.json in the user's
+  config directory, and has the form:
+
+    {
+      "default": "work",
+      "profiles": {
+        "work": {
+          "username": "...",
+          "password": "...",
+          "account_reference": "EX0000000",
+          "base_url": "https://api.esendex.com/"
+        }
+      }
+    }
+
+  Use the login command to store a session in a profile instead of a password.
 
   Commands: {{range .}}
     {{.Name | printf "%-15s"}} # {{.Short}}{{end}}
@@ -44,44 +105,131 @@ var templates = hadfield.Templates{
 func main() {
 	flag.Parse()
 
-	if *username == "" || *password == "" {
-		log.Fatal("Both --username and --password options are required.")
+	if err := checkOutput(*output); err != nil {
+		log.Fatal(err)
+	}
+
+	path, err := configPath()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg, err := readConfig(path)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	client := esendex.New(*username, *password)
+	s := resolveSettings(cfg)
+
+	*accountReference = s.AccountReference
+
+	client, err := s.client()
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	commands := hadfield.Commands{
 		receivedCmd(client),
 		sentCmd(client),
 		messageCmd(client),
 		accountsCmd(client),
+		sendCmd(client),
+		sendCSVCmd(client),
+		batchesCmd(client),
+		batchCmd(client),
+		exportCmd(client),
 	}
 
+	for _, cmd := range commands {
+		requireCredentials(cmd, s)
+	}
+
+	commands = append(commands,
+		loginCmd(cfg, path, s),
+		webhookCmd(),
+		mockServerCmd(),
+	)
+
 	hadfield.Run(commands, templates)
 }
 
+// requireCredentials wraps the command so that it fails before running if no
+// credentials have been given.
+func requireCredentials(cmd *hadfield.Command, s *settings) {
+	run := cmd.Run
+
+	cmd.Run = func(cmd *hadfield.Command, args []string) {
+		if s.ProfileMissing {
+			log.Fatalf("No profile named %q, use login to create it.", s.ProfileName)
+		}
+
+		if !s.hasCredentials() {
+			log.Fatal("Both a username and password, or a profile with a session, are required. See esendex help.")
+		}
+
+		run(cmd, args)
+	}
+}
+
 func receivedCmd(client *esendex.Client) *hadfield.Command {
-	var page int
+	var (
+		page     int
+		follow   bool
+		interval time.Duration
+		markRead bool
+		execCmd  string
+	)
 
 	cmd := &hadfield.Command{
 		Usage: "received [options]",
 		Short: "lists received messages",
 		Long: `
-  Received displays a list of received messages.
+  Received displays a list of received messages. If --account-reference is
+  given only messages received by that account are listed.
+
+  With --follow the inbox is checked for new messages until interrupted, and
+  each is displayed with its body as it arrives.
 
     --page NUM       # Display given page
+    --follow         # Display new messages as they arrive
+    --interval DUR   # Time to wait between checks when following (default: 10s)
+    --mark-read      # Mark messages read once displayed
+    --exec CMD       # Run CMD with each new message as JSON on stdin, messages
+                     #   are only marked read if CMD succeeds
 `,
 		Run: func(cmd *hadfield.Command, args []string) {
-			resp, err := client.Received()
+			received := client.Received
+			if *accountReference != "" {
+				received = client.Account(*accountReference).Received
+			}
+
+			if follow {
+				f := &follower{
+					Client:   client,
+					Received: received,
+					Interval: interval,
+					MarkRead: markRead,
+					Exec:     execCmd,
+				}
+
+				f.Run()
+				return
+			}
+
+			resp, err := received(pageOpts(page))
 			if err != nil {
 				log.Fatal(err)
 			}
 
-			pretty.PrettyPrint(resp.Messages)
+			printOutput(resp.Messages)
 		},
 	}
 
-	cmd.Flag.IntVar(&page, "page", 0, "")
+	cmd.Flag.IntVar(&page, "page", 1, "")
+	cmd.Flag.BoolVar(&follow, "follow", false, "")
+	cmd.Flag.DurationVar(&interval, "interval", 10*time.Second, "")
+	cmd.Flag.BoolVar(&markRead, "mark-read", false, "")
+	cmd.Flag.StringVar(&execCmd, "exec", "", "")
 
 	return cmd
 }
@@ -93,17 +241,28 @@ func sentCmd(client *esendex.Client) *hadfield.Command {
 		Usage: "sent [options]",
 		Short: "lists sent messages",
 		Long: `
-  Sent displays a list of sent messages.
+  Sent displays a list of sent messages. If --account-reference is given only
+  messages sent by that account are listed.
 
     --page NUM       # Display given page
 `,
 		Run: func(cmd *hadfield.Command, args []string) {
-			resp, err := client.Sent(pageOpts(page))
+			var (
+				resp *esendex.SentMessagesResponse
+				err  error
+			)
+
+			if *accountReference != "" {
+				resp, err = client.Account(*accountReference).Sent(pageOpts(page))
+			} else {
+				resp, err = client.Sent(pageOpts(page))
+			}
+
 			if err != nil {
 				log.Fatal(err)
 			}
 
-			pretty.PrettyPrint(resp.Messages)
+			printOutput(resp.Messages)
 		},
 	}
 
@@ -129,7 +288,7 @@ func messageCmd(client *esendex.Client) *hadfield.Command {
 				log.Fatal(err)
 			}
 
-			pretty.PrettyPrint(resp)
+			printOutput(resp)
 		},
 	}
 }
@@ -147,7 +306,7 @@ func accountsCmd(client *esendex.Client) *hadfield.Command {
 				log.Fatal(err)
 			}
 
-			pretty.PrettyPrint(resp.Accounts)
+			printOutput(resp.Accounts)
 		},
 	}
 }
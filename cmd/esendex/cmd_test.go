@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"hawx.me/code/hadfield"
+)
+
+func TestParseInterspersed(t *testing.T) {
+	testCases := []struct {
+		args       []string
+		positional []string
+		template   string
+		yes        bool
+	}{
+		{[]string{"FILE"}, []string{"FILE"}, "", false},
+		{[]string{"--template", "Hi", "FILE"}, []string{"FILE"}, "Hi", false},
+		{[]string{"FILE", "--template", "Hi {{.Name}}"}, []string{"FILE"}, "Hi {{.Name}}", false},
+		{[]string{"watch", "--yes", "ID"}, []string{"watch", "ID"}, "", true},
+		{[]string{"FILE", "-", "--yes"}, []string{"FILE", "-"}, "", true},
+		{[]string{"FILE", "--", "--yes"}, []string{"FILE", "--yes"}, "", false},
+		{[]string{"--yes", "--", "--template"}, []string{"--template"}, "", true},
+	}
+
+	for _, tc := range testCases {
+		var (
+			template string
+			yes      bool
+		)
+
+		cmd := &hadfield.Command{}
+		cmd.Flag.StringVar(&template, "template", "", "")
+		cmd.Flag.BoolVar(&yes, "yes", false, "")
+
+		assert.Equal(t, tc.positional, parseInterspersed(cmd, tc.args), "%v", tc.args)
+		assert.Equal(t, tc.template, template, "%v", tc.args)
+		assert.Equal(t, tc.yes, yes, "%v", tc.args)
+	}
+}
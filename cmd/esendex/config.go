@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/esendex/esendex-go-sdk"
+)
+
+// profile is a named set of settings stored in the config file.
+type profile struct {
+	Username         string `json:"username,omitempty"`
+	Password         string `json:"password,omitempty"`
+	Session          string `json:"session,omitempty"`
+	AccountReference string `json:"account_reference,omitempty"`
+	BaseURL          string `json:"base_url,omitempty"`
+}
+
+// config is the contents of the config file.
+type config struct {
+	Default  string              `json:"default,omitempty"`
+	Profiles map[string]*profile `json:"profiles"`
+}
+
+// configPath returns the location of the config file, which is given by
+// ESENDEX_CONFIG or defaults to esendex/config.json in the user's config
+// directory.
+func configPath() (string, error) {
+	if path := os.Getenv("ESENDEX_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "esendex", "config.json"), nil
+}
+
+// readConfig reads the config file, returning an empty config if it does not
+// exist.
+func readConfig(path string) (*config, error) {
+	cfg := &config{Profiles: map[string]*profile{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*profile{}
+	}
+
+	return cfg, nil
+}
+
+// writeConfig writes the config file so that only the current user can read
+// it, as it contains credentials.
+func writeConfig(path string, cfg *config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// settings are the options used to create the client, resolved from the
+// command line, environment and selected profile.
+type settings struct {
+	profile
+	ProfileName string
+
+	// ProfileMissing is set when the profile was selected by --profile or
+	// ESENDEX_PROFILE, but is not in the config file. This is only valid when
+	// logging in to create it.
+	ProfileMissing bool
+}
+
+// resolveSettings combines the settings from, in order of precedence, the
+// command line flags, the ESENDEX_* environment variables and the profile named
+// by --profile, ESENDEX_PROFILE or the config file's default.
+func resolveSettings(cfg *config) *settings {
+	s := &settings{
+		ProfileName: first(*profileName, os.Getenv("ESENDEX_PROFILE"), cfg.Default),
+	}
+
+	var p profile
+	if s.ProfileName != "" {
+		if stored, ok := cfg.Profiles[s.ProfileName]; ok {
+			p = *stored
+		} else {
+			s.ProfileMissing = true
+		}
+	}
+
+	s.Username = first(*username, os.Getenv("ESENDEX_USERNAME"), p.Username)
+	s.Password = first(*password, os.Getenv("ESENDEX_PASSWORD"), p.Password)
+	s.AccountReference = first(*accountReference, os.Getenv("ESENDEX_ACCOUNT_REFERENCE"), p.AccountReference)
+	s.BaseURL = first(*baseURL, os.Getenv("ESENDEX_BASE_URL"), p.BaseURL)
+
+	// A stored session is only used when no password has been given, so that
+	// the credentials can be used to log in again.
+	if s.Password == "" {
+		s.Session = first(os.Getenv("ESENDEX_SESSION"), p.Session)
+	}
+
+	return s
+}
+
+func (s *settings) hasCredentials() bool {
+	return s.Session != "" || (s.Username != "" && s.Password != "")
+}
+
+// client returns a client using the settings. It does not check that any
+// credentials have been given.
+func (s *settings) client() (*esendex.Client, error) {
+	var client *esendex.Client
+	if s.Session != "" {
+		client = esendex.NewSession(s.Session)
+	} else {
+		client = esendex.New(s.Username, s.Password)
+	}
+
+	if s.BaseURL != "" {
+		u, err := url.Parse(s.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL: %w", err)
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+
+		client.BaseURL = u
+	}
+
+	return client, nil
+}
+
+func first(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
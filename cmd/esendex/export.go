@@ -0,0 +1,378 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+	"hawx.me/code/hadfield"
+)
+
+// exportedSentMessage is the output for a sent message in an export.
+type exportedSentMessage struct {
+	esendex.SentMessageResponse
+	Body         string
+	CharacterSet string
+}
+
+// exportedReceivedMessage is the output for a received message in an export.
+type exportedReceivedMessage struct {
+	esendex.ReceivedMessageResponse
+	Body         string
+	CharacterSet string
+}
+
+// exportState records the progress of an export, so that it can be resumed. It
+// is written next to the output file after each page.
+type exportState struct {
+	Kind       string    `json:"kind"`
+	Account    string    `json:"account,omitempty"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Format     string    `json:"format"`
+	Bodies     bool      `json:"bodies"`
+	StartIndex int       `json:"start_index"`
+	Written    int64     `json:"written"`
+}
+
+func exportCmd(client *esendex.Client) *hadfield.Command {
+	var (
+		from     string
+		to       string
+		account  string
+		out      string
+		format   string
+		bodies   bool
+		resume   bool
+		pageSize int
+	)
+
+	cmd := &hadfield.Command{
+		Usage: "export [options] sent|received",
+		Short: "exports message history to a file",
+		Long: `
+  Export writes every sent or received message between two times to a CSV or
+  NDJSON file, fetching as many pages as needed.
+
+  Progress is saved to OUT.state after each page. If the export is interrupted
+  it can be continued with --resume, which uses the same range and options as
+  the original export.
+
+  Dates are either RFC3339 times or of the form 2006-01-02, which is taken as
+  midnight UTC.
+
+    --from DATE        # Start of the range (required)
+    --to DATE          # End of the range (default: now)
+    --account REF      # Only export messages for the account (default:
+                       #   --account-reference)
+    --out FILE         # File to write to (required)
+    --format FORMAT    # Either csv or ndjson (default: from the extension of
+                       #   --out, otherwise csv)
+    --bodies           # Fetch the full body of each message
+    --resume           # Continue an interrupted export
+    --page-size NUM    # Number of messages to request at a time (default: 100)
+`,
+		Run: func(cmd *hadfield.Command, args []string) {
+			args = parseInterspersed(cmd, args)
+
+			if out == "" {
+				log.Fatal("The --out option is required.")
+			}
+			if pageSize < 1 {
+				log.Fatal("--page-size must be at least 1")
+			}
+
+			statePath := out + ".state"
+
+			var state exportState
+			if resume {
+				data, err := os.ReadFile(statePath)
+				if err != nil {
+					log.Fatalf("Cannot resume: %v", err)
+				}
+				if err := json.Unmarshal(data, &state); err != nil {
+					log.Fatalf("Cannot resume: %s: %v", statePath, err)
+				}
+			} else {
+				if len(args) < 1 || (args[0] != "sent" && args[0] != "received") {
+					log.Fatal("Require sent or received parameter")
+				}
+				if from == "" {
+					log.Fatal("The --from option is required.")
+				}
+
+				var err error
+				state, err = newExportState(args[0], first(account, *accountReference), from, to, out, format, bodies, time.Now())
+				if err != nil {
+					log.Fatal(err)
+				}
+			}
+
+			if err := runExport(client, out, statePath, &state, pageSize, os.Stderr); err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+
+	cmd.Flag.StringVar(&from, "from", "", "")
+	cmd.Flag.StringVar(&to, "to", "", "")
+	cmd.Flag.StringVar(&account, "account", "", "")
+	cmd.Flag.StringVar(&out, "out", "", "")
+	cmd.Flag.StringVar(&format, "format", "", "")
+	cmd.Flag.BoolVar(&bodies, "bodies", false, "")
+	cmd.Flag.BoolVar(&resume, "resume", false, "")
+	cmd.Flag.IntVar(&pageSize, "page-size", 100, "")
+
+	return cmd
+}
+
+// newExportState returns the state for a new export of kind, which is either
+// sent or received. The range ends at to, or if that is empty just after now:
+// the API compares times to the second, so the end is rounded up to include
+// messages sent earlier in the current second.
+func newExportState(kind, account, from, to, out, format string, bodies bool, now time.Time) (exportState, error) {
+	state := exportState{
+		Kind:    kind,
+		Account: account,
+		Format:  format,
+		Bodies:  bodies,
+		To:      now.UTC().Truncate(time.Second).Add(time.Second),
+	}
+
+	var err error
+	if state.From, err = parseDate(from); err != nil {
+		return state, fmt.Errorf("--from: %w", err)
+	}
+	if to != "" {
+		if state.To, err = parseDate(to); err != nil {
+			return state, fmt.Errorf("--to: %w", err)
+		}
+	}
+
+	if state.Format == "" {
+		switch strings.ToLower(filepath.Ext(out)) {
+		case ".ndjson", ".jsonl":
+			state.Format = "ndjson"
+		default:
+			state.Format = "csv"
+		}
+	}
+	if state.Format != "csv" && state.Format != "ndjson" {
+		return state, errors.New("--format must be one of csv or ndjson")
+	}
+
+	return state, nil
+}
+
+// runExport writes the pages of messages described by state to out, saving the
+// state to statePath after each page and reporting progress to w. It continues
+// from state, so is used both to start and to resume an export.
+func runExport(client *esendex.Client, out, statePath string, state *exportState, pageSize int, w io.Writer) error {
+	var (
+		file *os.File
+		err  error
+	)
+
+	if state.StartIndex > 0 || state.Written > 0 {
+		// Anything written after the last saved page is from a page that did
+		// not complete, so is dropped and fetched again.
+		if file, err = os.OpenFile(out, os.O_WRONLY, 0644); err != nil {
+			return err
+		}
+		if err := file.Truncate(state.Written); err != nil {
+			file.Close()
+			return err
+		}
+		if _, err := file.Seek(state.Written, io.SeekStart); err != nil {
+			file.Close()
+			return err
+		}
+	} else {
+		if file, err = os.Create(out); err != nil {
+			return err
+		}
+	}
+	defer file.Close()
+
+	var rowType reflect.Type
+	if state.Kind == "sent" {
+		rowType = reflect.TypeOf(exportedSentMessage{})
+	} else {
+		rowType = reflect.TypeOf(exportedReceivedMessage{})
+	}
+
+	if state.Format == "csv" && state.Written == 0 {
+		if err := writeExportRows(file, state.Format, reflect.MakeSlice(reflect.SliceOf(rowType), 0, 0).Interface(), true); err != nil {
+			return err
+		}
+		if err := saveExportState(file, statePath, state); err != nil {
+			return err
+		}
+	}
+
+	for {
+		opts := []esendex.Option{
+			esendex.Between(state.From, state.To),
+			esendex.Page(state.StartIndex, pageSize),
+		}
+
+		rows, total, err := fetchExportPage(client, state, opts)
+		if err != nil {
+			return err
+		}
+
+		if err := writeExportRows(file, state.Format, rows, false); err != nil {
+			return err
+		}
+
+		count := reflect.ValueOf(rows).Len()
+		state.StartIndex += count
+
+		if err := saveExportState(file, statePath, state); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "Exported %d of %d\n", state.StartIndex, total)
+
+		if count == 0 || state.StartIndex >= total {
+			break
+		}
+	}
+
+	return os.Remove(statePath)
+}
+
+// fetchExportPage returns a slice of exportedSentMessage or
+// exportedReceivedMessage, and the total number of messages in the range.
+func fetchExportPage(client *esendex.Client, state *exportState, opts []esendex.Option) (interface{}, int, error) {
+	if state.Kind == "sent" {
+		fetch := client.Sent
+		if state.Account != "" {
+			fetch = client.Account(state.Account).Sent
+		}
+
+		resp, err := fetch(opts...)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		rows := make([]exportedSentMessage, len(resp.Messages))
+		for i, message := range resp.Messages {
+			rows[i] = exportedSentMessage{SentMessageResponse: message}
+
+			if state.Bodies {
+				body, err := client.Body(message)
+				if err != nil {
+					return nil, 0, fmt.Errorf("%s: %w", message.ID, err)
+				}
+				rows[i].Body = body.Text
+				rows[i].CharacterSet = body.CharacterSet
+			}
+		}
+
+		return rows, resp.TotalCount, nil
+	}
+
+	fetch := client.Received
+	if state.Account != "" {
+		fetch = client.Account(state.Account).Received
+	}
+
+	resp, err := fetch(opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows := make([]exportedReceivedMessage, len(resp.Messages))
+	for i, message := range resp.Messages {
+		rows[i] = exportedReceivedMessage{ReceivedMessageResponse: message}
+
+		if state.Bodies {
+			body, err := client.Body(message)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%s: %w", message.ID, err)
+			}
+			rows[i].Body = body.Text
+			rows[i].CharacterSet = body.CharacterSet
+		}
+	}
+
+	return rows, resp.TotalCount, nil
+}
+
+// writeExportRows writes the rows, or for csv only the header if header is set.
+func writeExportRows(w io.Writer, format string, rows interface{}, header bool) error {
+	if format == "ndjson" {
+		return writeOutput(w, "ndjson", rows)
+	}
+
+	cw := csv.NewWriter(w)
+
+	v := reflect.ValueOf(rows)
+	if header {
+		var columns []string
+		for _, f := range flatten(toObject(reflect.Zero(v.Type().Elem())), "") {
+			columns = append(columns, f.Name)
+		}
+		cw.Write(columns)
+	} else {
+		for i := 0; i < v.Len(); i++ {
+			fields := flatten(toObject(v.Index(i)), "")
+			row := make([]string, len(fields))
+			for j, f := range fields {
+				row[j] = f.Value.(string)
+			}
+			cw.Write(row)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// saveExportState syncs the output file then records how much of it has been
+// written, so that a resumed export never keeps a partial page.
+func saveExportState(file *os.File, statePath string, state *exportState) error {
+	if err := file.Sync(); err != nil {
+		return err
+	}
+
+	written, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	state.Written = written
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, statePath)
+}
+
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, errors.New("must be an RFC3339 time or a date of the form 2006-01-02")
+}
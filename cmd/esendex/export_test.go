@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+	"github.com/esendex/esendex-go-sdk/esendextest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDate(t *testing.T) {
+	testCases := map[string]time.Time{
+		"2024-01-02":                time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		"2024-01-02T03:04:05Z":      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"2024-01-02T03:04:05+01:00": time.Date(2024, 1, 2, 2, 4, 5, 0, time.UTC),
+	}
+
+	for value, expected := range testCases {
+		actual, err := parseDate(value)
+		if assert.Nil(t, err, value) {
+			assert.True(t, expected.Equal(actual), value)
+		}
+	}
+
+	for _, value := range []string{"", "yesterday", "02/01/2024", "2024-01-02 03:04"} {
+		_, err := parseDate(value)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestNewExportState(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC)
+
+	assert := assert.New(t)
+
+	state, err := newExportState("sent", "EX0000000", "2024-01-01", "", "out.csv", "", false, now)
+	if assert.Nil(err) {
+		assert.Equal("sent", state.Kind)
+		assert.Equal("EX0000000", state.Account)
+		assert.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), state.From)
+		assert.Equal(time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC), state.To)
+		assert.Equal("csv", state.Format)
+	}
+
+	state, err = newExportState("received", "", "2024-01-01", "2024-01-02", "out.csv", "", true, now)
+	if assert.Nil(err) {
+		assert.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), state.To)
+		assert.True(state.Bodies)
+	}
+
+	formats := map[[2]string]string{
+		{"out.csv", ""}:       "csv",
+		{"out.ndjson", ""}:    "ndjson",
+		{"OUT.JSONL", ""}:     "ndjson",
+		{"out.txt", ""}:       "csv",
+		{"out.csv", "ndjson"}: "ndjson",
+		{"out.ndjson", "csv"}: "csv",
+	}
+
+	for args, expected := range formats {
+		state, err := newExportState("sent", "", "2024-01-01", "", args[0], args[1], false, now)
+		if assert.Nil(err, args) {
+			assert.Equal(expected, state.Format, args)
+		}
+	}
+
+	_, err = newExportState("sent", "", "2024-01-01", "", "out.csv", "xml", false, now)
+	assert.EqualError(err, "--format must be one of csv or ndjson")
+
+	_, err = newExportState("sent", "", "yesterday", "", "out.csv", "", false, now)
+	assert.NotNil(err)
+
+	_, err = newExportState("sent", "", "2024-01-01", "tomorrow", "out.csv", "", false, now)
+	assert.NotNil(err)
+}
+
+// newExportServer returns a server with count messages sent from an account.
+func newExportServer(t *testing.T, count int) *esendextest.Server {
+	s := esendextest.NewServer()
+	s.AddAccount(esendextest.Account{Reference: "EX0000000", Address: "447700900000"})
+
+	account := s.Client().Account("EX0000000")
+	for i := 0; i < count; i++ {
+		if _, err := account.Send([]esendex.Message{{To: "447700900001", Body: "Hi"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return s
+}
+
+func exportedIDs(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, record := range records[1:] {
+		ids = append(ids, record[0])
+	}
+
+	return ids
+}
+
+func TestRunExport(t *testing.T) {
+	s := newExportServer(t, 7)
+	defer s.Close()
+
+	out := filepath.Join(t.TempDir(), "sent.csv")
+	state, _ := newExportState("sent", "EX0000000", "2000-01-01", "", out, "", false, time.Now())
+
+	var progress strings.Builder
+	err := runExport(s.Client(), out, out+".state", &state, 3, &progress)
+
+	assert := assert.New(t)
+
+	if assert.Nil(err) {
+		assert.Equal("Exported 3 of 7\nExported 6 of 7\nExported 7 of 7\n", progress.String())
+		assert.Len(exportedIDs(t, out), 7)
+
+		_, err := os.Stat(out + ".state")
+		assert.True(os.IsNotExist(err))
+	}
+}
+
+func TestRunExportResumes(t *testing.T) {
+	s := newExportServer(t, 7)
+	defer s.Close()
+
+	dir := t.TempDir()
+
+	complete := filepath.Join(dir, "complete.csv")
+	state, _ := newExportState("sent", "EX0000000", "2000-01-01", "", complete, "", false, time.Now())
+	if err := runExport(s.Client(), complete, complete+".state", &state, 3, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fail the third page, as if the export was interrupted.
+	pages := 0
+	failing := s.Client()
+	failing.Middleware = []esendex.Middleware{
+		func(call *esendex.Call, next esendex.Handler) (*http.Response, error) {
+			if pages++; pages == 3 {
+				return nil, errors.New("interrupted")
+			}
+			return next(call)
+		},
+	}
+
+	out := filepath.Join(dir, "sent.csv")
+	statePath := out + ".state"
+	state, _ = newExportState("sent", "EX0000000", "2000-01-01", "", out, "", false, time.Now())
+
+	assert := assert.New(t)
+
+	err := runExport(failing, out, statePath, &state, 3, ioutil.Discard)
+	assert.EqualError(err, "interrupted")
+	assert.Len(exportedIDs(t, out), 6)
+
+	// Leave part of a row after the saved progress, as a crash mid-page would.
+	file, _ := os.OpenFile(out, os.O_WRONLY|os.O_APPEND, 0644)
+	file.WriteString("partial,row")
+	file.Close()
+
+	data, err := os.ReadFile(statePath)
+	if !assert.Nil(err) {
+		return
+	}
+
+	var resumed exportState
+	if !assert.Nil(json.Unmarshal(data, &resumed)) {
+		return
+	}
+	assert.Equal(6, resumed.StartIndex)
+
+	if assert.Nil(runExport(s.Client(), out, statePath, &resumed, 3, ioutil.Discard)) {
+		expected, _ := os.ReadFile(complete)
+		actual, _ := os.ReadFile(out)
+		assert.Equal(string(expected), string(actual))
+
+		ids := exportedIDs(t, out)
+		seen := map[string]bool{}
+		for _, id := range ids {
+			assert.False(seen[id], id)
+			seen[id] = true
+		}
+		assert.Len(seen, 7)
+	}
+}
+
+func TestRunExportNDJSON(t *testing.T) {
+	s := newExportServer(t, 2)
+	defer s.Close()
+
+	out := filepath.Join(t.TempDir(), "sent.ndjson")
+	state, _ := newExportState("sent", "", "2000-01-01", "", out, "", false, time.Now())
+
+	assert := assert.New(t)
+
+	if assert.Nil(runExport(s.Client(), out, out+".state", &state, 100, ioutil.Discard)) {
+		data, _ := os.ReadFile(out)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+		if assert.Len(lines, 2) {
+			var row map[string]interface{}
+			if assert.Nil(json.Unmarshal([]byte(lines[0]), &row)) {
+				assert.Contains(row, "id")
+				assert.Contains(row, "batch_id")
+				assert.Contains(row, "body")
+			}
+		}
+	}
+}
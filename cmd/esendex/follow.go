@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+)
+
+// followPageSize is the number of the most recent messages checked on each
+// poll. Any more than this arriving between polls will be missed.
+const followPageSize = 100
+
+// followedMessage is the output for a message received while following.
+type followedMessage struct {
+	ID           string
+	ReceivedAt   time.Time
+	Type         esendex.MessageType
+	From         string
+	To           string
+	Body         string
+	CharacterSet string
+}
+
+// follower polls an inbox for new messages.
+type follower struct {
+	Client   *esendex.Client
+	Received func(opts ...esendex.Option) (*esendex.ReceivedMessagesResponse, error)
+
+	Interval time.Duration
+	MarkRead bool
+	Exec     string
+}
+
+// Run polls until the process is stopped. Messages already in the inbox when
+// it starts are not displayed.
+func (f *follower) Run() {
+	var seen map[string]bool
+
+	for {
+		resp, err := f.Received(esendex.Page(0, followPageSize))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			time.Sleep(f.Interval)
+			continue
+		}
+
+		current := map[string]bool{}
+		var fresh []esendex.ReceivedMessageResponse
+		for _, message := range resp.Messages {
+			current[message.ID] = true
+
+			if seen != nil && !seen[message.ID] {
+				fresh = append(fresh, message)
+			}
+		}
+		seen = current
+
+		// The newest messages are returned first, so go backwards to handle them
+		// in the order they arrived.
+		for i := len(fresh) - 1; i >= 0; i-- {
+			f.handle(fresh[i])
+		}
+
+		time.Sleep(f.Interval)
+	}
+}
+
+func (f *follower) handle(message esendex.ReceivedMessageResponse) {
+	m := followedMessage{
+		ID:         message.ID,
+		ReceivedAt: message.ReceivedAt,
+		Type:       message.Type,
+		From:       message.From,
+		To:         message.To,
+		Body:       message.Summary,
+	}
+
+	if body, err := f.Client.Body(message); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: could not fetch body: %v\n", message.ID, err)
+	} else {
+		m.Body = body.Text
+		m.CharacterSet = body.CharacterSet
+	}
+
+	if *output == "table" {
+		fmt.Printf("%s  %-16s  %s\n", m.ReceivedAt.Local().Format("15:04:05"), m.From, m.Body)
+	} else {
+		printOutput(m)
+	}
+
+	if f.Exec != "" {
+		var buf bytes.Buffer
+		if err := writeOutput(&buf, "ndjson", m); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", message.ID, err)
+			return
+		}
+
+		cmd := exec.Command("sh", "-c", f.Exec)
+		cmd.Stdin = &buf
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		// Leave the message unread if the command failed, so that it is clear
+		// it has not been dealt with.
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s: %v\n", message.ID, f.Exec, err)
+			return
+		}
+	}
+
+	if f.MarkRead {
+		if err := f.Client.MarkRead(message.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: could not mark read: %v\n", message.ID, err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"hawx.me/code/hadfield"
+)
+
+func loginCmd(cfg *config, path string, s *settings) *hadfield.Command {
+	return &hadfield.Command{
+		Usage: "login",
+		Short: "stores a session for a profile",
+		Long: `
+  Login creates a session with the given credentials and stores it, along with
+  the username, --account-reference and --base-url, in the profile named by
+  --profile (default: default). The password is not stored. Any credentials not
+  given as options or environment variables are asked for.
+
+  The first profile stored becomes the default used when --profile is not
+  given.
+`,
+		Run: func(cmd *hadfield.Command, args []string) {
+			reader := bufio.NewReader(os.Stdin)
+
+			if s.Username == "" {
+				s.Username = prompt(reader, "Username: ", false)
+			}
+			if s.Password == "" {
+				s.Password = prompt(reader, "Password: ", true)
+			}
+			if s.Username == "" || s.Password == "" {
+				log.Fatal("Both a username and password are required to log in.")
+			}
+
+			s.Session = ""
+			client, err := s.client()
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			session, err := client.CreateSession()
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			name := first(s.ProfileName, "default")
+
+			cfg.Profiles[name] = &profile{
+				Username:         s.Username,
+				Session:          session,
+				AccountReference: s.AccountReference,
+				BaseURL:          s.BaseURL,
+			}
+			if cfg.Default == "" {
+				cfg.Default = name
+			}
+
+			if err := writeConfig(path, cfg); err != nil {
+				log.Fatal(err)
+			}
+
+			fmt.Fprintf(os.Stderr, "Logged in as %s, session stored in profile %q\n", s.Username, name)
+		},
+	}
+}
+
+// prompt asks for a value on stdin. If secret is set, and stdin is a terminal,
+// the value is not echoed.
+func prompt(reader *bufio.Reader, label string, secret bool) string {
+	fmt.Fprint(os.Stderr, label)
+
+	if secret && setEcho(false) == nil {
+		defer func() {
+			setEcho(true)
+			fmt.Fprintln(os.Stderr)
+		}()
+	}
+
+	value, _ := reader.ReadString('\n')
+	return strings.TrimSpace(value)
+}
+
+func setEcho(on bool) error {
+	arg := "-echo"
+	if on {
+		arg = "echo"
+	}
+
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
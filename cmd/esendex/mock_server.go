@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+	"github.com/esendex/esendex-go-sdk/esendextest"
+	"gopkg.in/yaml.v3"
+	"hawx.me/code/hadfield"
+)
+
+// fixture is the data a mock server is seeded with. It is read as YAML, so
+// JSON can be used too.
+type fixture struct {
+	Username string                    `yaml:"username"`
+	Password string                    `yaml:"password"`
+	Accounts []fixtureAccount          `yaml:"accounts"`
+	Inbox    []fixtureInbound          `yaml:"inbox"`
+	Batches  []fixtureBatch            `yaml:"batches"`
+	Outcomes map[string]fixtureOutcome `yaml:"outcomes"`
+}
+
+type fixtureAccount struct {
+	ID                string    `yaml:"id"`
+	Reference         string    `yaml:"reference"`
+	Label             string    `yaml:"label"`
+	Address           string    `yaml:"address"`
+	Type              string    `yaml:"type"`
+	MessagesRemaining int       `yaml:"messages_remaining"`
+	ExpiresOn         time.Time `yaml:"expires_on"`
+	Role              string    `yaml:"role"`
+}
+
+type fixtureInbound struct {
+	AccountReference string `yaml:"account_reference"`
+	From             string `yaml:"from"`
+	Body             string `yaml:"body"`
+}
+
+type fixtureBatch struct {
+	AccountReference string           `yaml:"account_reference"`
+	From             string           `yaml:"from"`
+	SendAt           time.Time        `yaml:"send_at"`
+	Status           string           `yaml:"status"`
+	Messages         []fixtureMessage `yaml:"messages"`
+}
+
+type fixtureMessage struct {
+	To   string `yaml:"to"`
+	Body string `yaml:"body"`
+	Type string `yaml:"type"`
+}
+
+type fixtureOutcome struct {
+	Delay   time.Duration `yaml:"delay"`
+	Failure *struct {
+		Code        int    `yaml:"code"`
+		Description string `yaml:"description"`
+		Permanent   bool   `yaml:"permanent"`
+	} `yaml:"failure"`
+}
+
+const exampleFixture = `
+    accounts:
+      - reference: EX0000000
+        address: "447700900000"
+        messages_remaining: 1000
+    inbox:
+      - account_reference: EX0000000
+        from: "447700900123"
+        body: Hello
+    batches:
+      - account_reference: EX0000000
+        status: Delivered
+        messages:
+          - to: "447700900456"
+            body: Your order has shipped
+    outcomes:
+      "447700900999":
+        delay: 30s
+        failure: {code: 5, description: Unknown number, permanent: true}
+`
+
+func mockServerCmd() *hadfield.Command {
+	var (
+		addr        string
+		fixturePath string
+		notifyURL   string
+		tick        time.Duration
+	)
+
+	cmd := &hadfield.Command{
+		Usage: "mock-server [options]",
+		Short: "runs a local fake of the API",
+		Long: `
+  Mock-server runs a fake of the Esendex REST API, which keeps the messages
+  sent to it, so that apps can be developed and tested without credentials.
+  Any username and password are accepted unless the fixture gives them.
+
+  Accounts, inbox messages, batches and delivery outcomes can be loaded from a
+  YAML or JSON fixture file, for example:
+` + exampleFixture + `
+  Batch statuses are one of Scheduled, Submitted, Sent, Delivered, Failed or
+  Cancelled. Messages sent to numbers without an outcome stay Submitted.
+
+    --addr ADDR        # Address to listen on (default: localhost:8080)
+    --fixture FILE     # Fixture to load
+    --notify-url URL   # Send push notifications to URL
+    --tick DUR         # How often to apply outcomes and release scheduled
+                       #   batches (default: 1s)
+`,
+		Run: func(cmd *hadfield.Command, args []string) {
+			var f fixture
+			if fixturePath != "" {
+				data, err := os.ReadFile(fixturePath)
+				if err != nil {
+					log.Fatal(err)
+				}
+
+				if err := yaml.Unmarshal(data, &f); err != nil {
+					log.Fatalf("%s: %v", fixturePath, err)
+				}
+			}
+
+			s, err := esendextest.NewServerAt(addr)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer s.Close()
+
+			s.NotifyURL = notifyURL
+			s.NotifyError = func(err error) {
+				fmt.Fprintf(os.Stderr, "could not notify: %v\n", err)
+			}
+
+			if err := seedServer(s, f); err != nil {
+				log.Fatal(err)
+			}
+
+			fmt.Fprintf(os.Stderr, "Fake Esendex API listening on %s\n", s.URL)
+			fmt.Fprintf(os.Stderr, "Use it with: esendex --base-url %s/ ...\n", s.URL)
+
+			stop := make(chan os.Signal, 1)
+			signal.Notify(stop, os.Interrupt)
+
+			ticker := time.NewTicker(tick)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					s.Tick()
+				case <-stop:
+					return
+				}
+			}
+		},
+	}
+
+	cmd.Flag.StringVar(&addr, "addr", "localhost:8080", "")
+	cmd.Flag.StringVar(&fixturePath, "fixture", "", "")
+	cmd.Flag.StringVar(&notifyURL, "notify-url", "", "")
+	cmd.Flag.DurationVar(&tick, "tick", time.Second, "")
+
+	return cmd
+}
+
+func seedServer(s *esendextest.Server, f fixture) error {
+	s.Username = f.Username
+	s.Password = f.Password
+
+	for _, a := range f.Accounts {
+		s.AddAccount(esendextest.Account{
+			ID:                a.ID,
+			Reference:         a.Reference,
+			Label:             a.Label,
+			Address:           a.Address,
+			Type:              a.Type,
+			MessagesRemaining: a.MessagesRemaining,
+			ExpiresOn:         a.ExpiresOn,
+			Role:              a.Role,
+		})
+	}
+
+	for number, o := range f.Outcomes {
+		outcome := esendextest.Outcome{Delay: o.Delay}
+		if o.Failure != nil {
+			outcome.Failure = &esendex.FailureReason{
+				Code:        o.Failure.Code,
+				Description: o.Failure.Description,
+				Permanent:   o.Failure.Permanent,
+			}
+		}
+
+		s.SetOutcome(number, outcome)
+	}
+
+	for i, m := range f.Inbox {
+		if _, err := s.Receive(m.AccountReference, m.From, m.Body); err != nil {
+			return fmt.Errorf("inbox message %d: %w", i+1, err)
+		}
+	}
+
+	client := s.Client()
+
+	for i, b := range f.Batches {
+		messages := make([]esendex.Message, len(b.Messages))
+		for j, m := range b.Messages {
+			messages[j] = esendex.Message{
+				To:   m.To,
+				From: b.From,
+				Body: m.Body,
+			}
+
+			switch strings.ToLower(m.Type) {
+			case "", "sms":
+			case "voice":
+				messages[j].MessageType = esendex.Voice
+			default:
+				return fmt.Errorf("batch %d: unknown message type %q", i+1, m.Type)
+			}
+		}
+
+		account := client.Account(b.AccountReference)
+
+		var (
+			resp *esendex.SendResponse
+			err  error
+		)
+		if b.SendAt.IsZero() {
+			resp, err = account.Send(messages)
+		} else {
+			resp, err = account.SendAt(b.SendAt, messages)
+		}
+		if err != nil {
+			return fmt.Errorf("batch %d: %w", i+1, err)
+		}
+
+		if b.Status != "" {
+			if err := s.SetBatchStatus(resp.BatchID, b.Status); err != nil {
+				return fmt.Errorf("batch %d: %w", i+1, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// field is a named value in an object. Values are nil, strings, numbers,
+// bools, objects or slices of these.
+type field struct {
+	Name  string
+	Value interface{}
+}
+
+// object is a struct converted for output. Field names are the snake_case form
+// of the Go field names, so they stay the same across all output formats.
+type object struct {
+	Fields []field
+
+	// Null is set when the object came from a nil pointer. The fields are still
+	// listed so that csv and table columns do not depend on the data.
+	Null bool
+
+	// Keyed is set when the object came from a map, so has fields that depend
+	// on the data.
+	Keyed bool
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	if o.Null {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o.Fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, _ := json.Marshal(f.Name)
+		buf.Write(name)
+		buf.WriteByte(':')
+
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// checkOutput returns an error if format is not a known output format.
+func checkOutput(format string) error {
+	switch format {
+	case "table", "json", "ndjson", "csv":
+		return nil
+	}
+
+	if strings.HasPrefix(format, "template=") {
+		_, err := template.New("output").Parse(strings.TrimPrefix(format, "template="))
+		return err
+	}
+
+	return fmt.Errorf("--output must be one of table, json, ndjson, csv or template=TEXT")
+}
+
+// printOutput writes v, either a struct or a slice of structs, to stdout in the
+// format given by --output.
+func printOutput(v interface{}) {
+	if err := writeOutput(os.Stdout, *output, v); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeOutput(w io.Writer, format string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	isList := rv.Kind() == reflect.Slice
+
+	var objects []object
+	if isList {
+		objects = make([]object, rv.Len())
+		for i := range objects {
+			objects[i] = toObject(rv.Index(i))
+		}
+	} else {
+		objects = []object{toObject(rv)}
+	}
+
+	// The columns come from the type rather than the data, so that an empty
+	// list still has a header.
+	var columns []string
+	if isList {
+		for _, f := range flatten(toObject(reflect.Zero(rv.Type().Elem())), "") {
+			columns = append(columns, f.Name)
+		}
+	}
+
+	switch {
+	case format == "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if isList {
+			return enc.Encode(objects)
+		}
+		return enc.Encode(objects[0])
+
+	case format == "ndjson":
+		enc := json.NewEncoder(w)
+		for _, o := range objects {
+			if err := enc.Encode(o); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case format == "csv":
+		cw := csv.NewWriter(w)
+		if !isList {
+			for _, f := range flatten(objects[0], "") {
+				columns = append(columns, f.Name)
+			}
+		}
+
+		cw.Write(columns)
+		for _, o := range objects {
+			fields := flatten(o, "")
+			row := make([]string, len(fields))
+			for i, f := range fields {
+				row[i] = f.Value.(string)
+			}
+			cw.Write(row)
+		}
+
+		cw.Flush()
+		return cw.Error()
+
+	case strings.HasPrefix(format, "template="):
+		text := strings.TrimPrefix(format, "template=")
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+
+		t, err := template.New("output").Parse(text)
+		if err != nil {
+			return err
+		}
+
+		for _, o := range objects {
+			if err := t.Execute(w, toTemplateData(o)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case format == "table":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+		if isList {
+			header := make([]string, len(columns))
+			for i, column := range columns {
+				header[i] = strings.ToUpper(column)
+			}
+			fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+			for _, o := range objects {
+				fields := flatten(o, "")
+				row := make([]string, len(fields))
+				for i, f := range fields {
+					row[i] = f.Value.(string)
+				}
+				fmt.Fprintln(tw, strings.Join(row, "\t"))
+			}
+		} else {
+			for _, f := range flatten(objects[0], "") {
+				fmt.Fprintf(tw, "%s:\t%s\n", f.Name, f.Value)
+			}
+		}
+
+		return tw.Flush()
+	}
+
+	return checkOutput(format)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// toObject converts the struct v, which may be a pointer, to an object.
+func toObject(v reflect.Value) object {
+	t := v.Type()
+	null := false
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if v.IsNil() {
+			null = true
+			v = reflect.Zero(t)
+		} else {
+			v = v.Elem()
+		}
+	}
+
+	o := object{Null: null}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		value := toValue(v.Field(i))
+
+		if sf.Anonymous {
+			if embedded, ok := value.(object); ok {
+				o.Fields = append(o.Fields, embedded.Fields...)
+				continue
+			}
+		}
+
+		o.Fields = append(o.Fields, field{Name: snakeCase(sf.Name), Value: value})
+	}
+
+	return o
+}
+
+func toValue(v reflect.Value) interface{} {
+	if v.Type() == timeType {
+		if t := v.Interface().(time.Time); !t.IsZero() {
+			return t.Format(time.RFC3339)
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.Type().Elem().Kind() == reflect.Struct && v.Type().Elem() != timeType {
+			return toObject(v)
+		}
+		if v.IsNil() {
+			return nil
+		}
+		return toValue(v.Elem())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if err, ok := v.Interface().(error); ok {
+			return err.Error()
+		}
+		return toValue(v.Elem())
+
+	case reflect.Struct:
+		return toObject(v)
+
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := map[string]reflect.Value{}
+		for _, key := range v.MapKeys() {
+			name := fmt.Sprint(key.Interface())
+			keys = append(keys, name)
+			values[name] = v.MapIndex(key)
+		}
+		sort.Strings(keys)
+
+		o := object{Keyed: true}
+		for _, key := range keys {
+			o.Fields = append(o.Fields, field{Name: key, Value: toValue(values[key])})
+		}
+		return o
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return []interface{}{}
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = toValue(v.Index(i))
+		}
+		return list
+
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// flatten returns the fields of o with nested objects expanded, so that
+// failure_reason becomes failure_reason_code and so on, and all values
+// formatted as strings.
+func flatten(o object, prefix string) []field {
+	var fields []field
+
+	for _, f := range o.Fields {
+		name := prefix + f.Name
+
+		if nested, ok := f.Value.(object); ok && !nested.Keyed {
+			for _, nf := range flatten(nested, name+"_") {
+				if nested.Null {
+					nf.Value = ""
+				}
+				fields = append(fields, nf)
+			}
+			continue
+		}
+
+		fields = append(fields, field{Name: name, Value: flatValue(f.Value)})
+	}
+
+	return fields
+}
+
+func flatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case object:
+		parts := make([]string, len(v.Fields))
+		for i, f := range v.Fields {
+			parts[i] = f.Name + "=" + flatValue(f.Value)
+		}
+		return strings.Join(parts, " ")
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = flatValue(item)
+		}
+		return strings.Join(parts, ";")
+	}
+
+	return fmt.Sprint(v)
+}
+
+// toTemplateData converts o to a map so templates can refer to fields by the
+// same names as the other formats, such as {{.batch_id}}.
+func toTemplateData(v interface{}) interface{} {
+	switch v := v.(type) {
+	case object:
+		if v.Null {
+			return nil
+		}
+		data := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			data[f.Name] = toTemplateData(f.Value)
+		}
+		return data
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = toTemplateData(item)
+		}
+		return list
+	}
+
+	return v
+}
+
+// snakeCase converts a Go field name like LastStatusAt or BatchID to
+// last_status_at or batch_id.
+func snakeCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
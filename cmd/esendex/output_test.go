@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/esendex/esendex-go-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+type outputRow struct {
+	BatchID       string
+	SettingsURI   string
+	FailureReason *esendex.FailureReason
+}
+
+func TestSnakeCase(t *testing.T) {
+	testCases := map[string]string{
+		"ID":                "id",
+		"URI":               "uri",
+		"BatchID":           "batch_id",
+		"SettingsURI":       "settings_uri",
+		"LastStatusAt":      "last_status_at",
+		"MessagesRemaining": "messages_remaining",
+		"HTTPClient":        "http_client",
+		"SMSCount":          "sms_count",
+		"Parts":             "parts",
+		"Line2":             "line2",
+	}
+
+	for name, expected := range testCases {
+		assert.Equal(t, expected, snakeCase(name), name)
+	}
+}
+
+func TestWriteOutputEmptyList(t *testing.T) {
+	testCases := map[string]string{
+		"csv":    "batch_id,settings_uri,failure_reason_code,failure_reason_description,failure_reason_permanent\n",
+		"table":  "BATCH_ID  SETTINGS_URI  FAILURE_REASON_CODE  FAILURE_REASON_DESCRIPTION  FAILURE_REASON_PERMANENT\n",
+		"json":   "[]\n",
+		"ndjson": "",
+	}
+
+	for format, expected := range testCases {
+		var buf bytes.Buffer
+
+		if assert.Nil(t, writeOutput(&buf, format, []outputRow{}), format) {
+			assert.Equal(t, expected, buf.String(), format)
+		}
+	}
+}
+
+func TestWriteOutputNilNestedObject(t *testing.T) {
+	rows := []outputRow{
+		{BatchID: "batchid", SettingsURI: "http://settings"},
+		{BatchID: "otherid", FailureReason: &esendex.FailureReason{Code: 5, Description: "Bad", Permanent: true}},
+	}
+
+	testCases := map[string]string{
+		"csv": "batch_id,settings_uri,failure_reason_code,failure_reason_description,failure_reason_permanent\n" +
+			"batchid,http://settings,,,\n" +
+			"otherid,,5,Bad,true\n",
+		"ndjson": `{"batch_id":"batchid","settings_uri":"http://settings","failure_reason":null}` + "\n" +
+			`{"batch_id":"otherid","settings_uri":"","failure_reason":{"code":5,"description":"Bad","permanent":true}}` + "\n",
+		"template={{.batch_id}} {{with .failure_reason}}{{.code}}{{else}}none{{end}}": "batchid none\notherid 5\n",
+	}
+
+	for format, expected := range testCases {
+		var buf bytes.Buffer
+
+		if assert.Nil(t, writeOutput(&buf, format, rows), format) {
+			assert.Equal(t, expected, buf.String(), format)
+		}
+	}
+}
+
+func TestWriteOutputSingleObject(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := writeOutput(&buf, "csv", &outputRow{BatchID: "batchid"})
+
+	if assert.Nil(t, err) {
+		assert.Equal(t, "batch_id,settings_uri,failure_reason_code,failure_reason_description,failure_reason_permanent\n"+
+			"batchid,,,,\n", buf.String())
+	}
+}
+
+func TestCheckOutput(t *testing.T) {
+	for _, format := range []string{"table", "json", "ndjson", "csv", "template={{.id}}"} {
+		assert.Nil(t, checkOutput(format), format)
+	}
+
+	for _, format := range []string{"xml", "template={{.id"} {
+		assert.NotNil(t, checkOutput(format), format)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+	"hawx.me/code/hadfield"
+)
+
+// sendResult is the output for a single sent message.
+type sendResult struct {
+	BatchID   string
+	MessageID string
+	URI       string
+}
+
+// stringsFlag is a flag that can be given multiple times.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func sendCmd(client *esendex.Client) *hadfield.Command {
+	var (
+		to          stringsFlag
+		from        string
+		body        string
+		messageType string
+		validity    int
+		at          string
+	)
+
+	cmd := &hadfield.Command{
+		Usage: "send [options]",
+		Short: "sends a message",
+		Long: `
+  Send dispatches a message from the account given by --account-reference. If
+  --body is not given the body is read from stdin.
+
+    --to NUMBER      # Recipient, can be given multiple times
+    --from FROM      # Override the default originator
+    --body TEXT      # Body of the message
+    --type TYPE      # Type of message, either sms or voice (default: sms)
+    --validity HRS   # Number of hours the message is valid for
+    --at TIME        # Schedule the message for the given RFC3339 time
+`,
+		Run: func(cmd *hadfield.Command, args []string) {
+			if *accountReference == "" {
+				log.Fatal("The --account-reference option is required.")
+			}
+
+			if len(to) == 0 {
+				log.Fatal("Require at least one --to option")
+			}
+
+			var t esendex.MessageType
+			switch strings.ToLower(messageType) {
+			case "sms":
+				t = esendex.SMS
+			case "voice":
+				t = esendex.Voice
+			default:
+				log.Fatal("--type must be one of sms or voice")
+			}
+
+			if body == "" {
+				data, err := ioutil.ReadAll(os.Stdin)
+				if err != nil {
+					log.Fatal(err)
+				}
+
+				body = strings.TrimRight(string(data), "\r\n")
+			}
+
+			if body == "" {
+				log.Fatal("Require a message body")
+			}
+
+			messages := make([]esendex.Message, len(to))
+			for i, number := range to {
+				messages[i] = esendex.Message{
+					To:          number,
+					MessageType: t,
+					Validity:    validity,
+					Body:        body,
+				}
+			}
+
+			account := client.Account(*accountReference)
+
+			var (
+				resp *esendex.SendResponse
+				err  error
+			)
+
+			switch {
+			case at != "":
+				sendAt, perr := time.Parse(time.RFC3339, at)
+				if perr != nil {
+					log.Fatal("--at must be an RFC3339 time, such as 2006-01-02T15:04:05Z")
+				}
+
+				for i := range messages {
+					messages[i].From = from
+				}
+
+				resp, err = account.SendAt(sendAt, messages)
+			case from != "":
+				resp, err = account.SendFrom(from, messages)
+			default:
+				resp, err = account.Send(messages)
+			}
+
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			for _, number := range resp.OptedOut {
+				fmt.Fprintf(os.Stderr, "Not sent to %s, which has opted out\n", number)
+			}
+
+			results := make([]sendResult, len(resp.Messages))
+			for i, message := range resp.Messages {
+				results[i] = sendResult{
+					BatchID:   resp.BatchID,
+					MessageID: message.ID,
+					URI:       message.URI,
+				}
+			}
+
+			printOutput(results)
+		},
+	}
+
+	cmd.Flag.Var(&to, "to", "")
+	cmd.Flag.StringVar(&from, "from", "", "")
+	cmd.Flag.StringVar(&body, "body", "", "")
+	cmd.Flag.StringVar(&messageType, "type", "sms", "")
+	cmd.Flag.IntVar(&validity, "validity", 0, "")
+	cmd.Flag.StringVar(&at, "at", "", "")
+
+	return cmd
+}
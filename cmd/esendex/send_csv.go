@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/esendex/esendex-go-sdk"
+	"hawx.me/code/hadfield"
+)
+
+// csvRow is a single recipient read from a CSV file, with the body rendered
+// from the template.
+type csvRow struct {
+	Line     int
+	To       string
+	Body     string
+	Segments int
+	Err      error
+
+	BatchID   string
+	MessageID string
+}
+
+// csvPreviewRow is the output for a row before it is sent.
+type csvPreviewRow struct {
+	Line     int
+	To       string
+	Segments int
+	Body     string
+	Error    error
+}
+
+func sendCSVCmd(client *esendex.Client) *hadfield.Command {
+	var (
+		tmpl      string
+		toColumn  string
+		from      string
+		chunkSize int
+		dryRun    bool
+		yes       bool
+		results   string
+	)
+
+	cmd := &hadfield.Command{
+		Usage: "send-csv [options] FILE",
+		Short: "sends messages to recipients listed in a CSV file",
+		Long: `
+  Send-csv dispatches a message to each row of a CSV file from the account
+  given by --account-reference. The first row of the file names the columns,
+  which can be used in the template, for example "Hi {{.Name}}".
+
+  A preview of the messages is displayed first, in the format given by
+  --output, with the number of segments each will use, and confirmation is
+  asked for before sending.
+
+    --template TEXT    # Template for the message body (required)
+    --to-column NAME   # Column containing the recipient (default: to)
+    --from FROM        # Override the default originator
+    --chunk-size NUM   # Number of messages to send per request (default: 50)
+    --dry-run          # Display the preview without sending
+    --yes              # Send without asking for confirmation
+    --results FILE     # Where to write results (default: FILE.results.csv)
+`,
+		Run: func(cmd *hadfield.Command, args []string) {
+			args = parseInterspersed(cmd, args)
+
+			if len(args) < 1 {
+				log.Fatal("Require FILE parameter")
+			}
+			if tmpl == "" {
+				log.Fatal("The --template option is required.")
+			}
+			if chunkSize < 1 {
+				log.Fatal("--chunk-size must be at least 1")
+			}
+
+			t, err := template.New("body").Option("missingkey=error").Parse(tmpl)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			rows, err := readCSVRows(args[0], toColumn, t)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			preview := make([]csvPreviewRow, len(rows))
+			valid, segments := 0, 0
+			for i, row := range rows {
+				preview[i] = csvPreviewRow{
+					Line:     row.Line,
+					To:       row.To,
+					Segments: row.Segments,
+					Body:     row.Body,
+					Error:    row.Err,
+				}
+
+				if row.Err == nil {
+					valid++
+					segments += row.Segments
+				}
+			}
+
+			printOutput(preview)
+			fmt.Fprintf(os.Stderr, "\n%d message(s), %d segment(s), %d row(s) with errors\n", valid, segments, len(rows)-valid)
+
+			if dryRun || valid == 0 {
+				return
+			}
+
+			if *accountReference == "" {
+				log.Fatal("The --account-reference option is required.")
+			}
+
+			if !yes && !confirm(fmt.Sprintf("Send %d message(s)?", valid)) {
+				return
+			}
+
+			sendCSVRows(client.Account(*accountReference), from, rows, chunkSize)
+
+			if results == "" {
+				results = strings.TrimSuffix(args[0], ".csv") + ".results.csv"
+			}
+
+			if err := writeCSVResults(results, rows); err != nil {
+				log.Fatal(err)
+			}
+
+			fmt.Fprintf(os.Stderr, "Results written to %s\n", results)
+		},
+	}
+
+	cmd.Flag.StringVar(&tmpl, "template", "", "")
+	cmd.Flag.StringVar(&toColumn, "to-column", "to", "")
+	cmd.Flag.StringVar(&from, "from", "", "")
+	cmd.Flag.IntVar(&chunkSize, "chunk-size", 50, "")
+	cmd.Flag.BoolVar(&dryRun, "dry-run", false, "")
+	cmd.Flag.BoolVar(&yes, "yes", false, "")
+	cmd.Flag.StringVar(&results, "results", "", "")
+
+	return cmd
+}
+
+func readCSVRows(path, toColumn string, t *template.Template) ([]*csvRow, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Spreadsheets often start CSV files with a byte order mark, which would
+	// otherwise become part of the first column name.
+	r := bufio.NewReader(file)
+	if bom, _ := r.Peek(3); bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		r.Discard(3)
+	}
+
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+
+	header := records[0]
+
+	toIndex := -1
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), toColumn) {
+			toIndex = i
+		}
+	}
+	if toIndex < 0 {
+		return nil, fmt.Errorf("%s has no %q column", path, toColumn)
+	}
+
+	rows := make([]*csvRow, len(records)-1)
+	for i, record := range records[1:] {
+		fields := map[string]string{}
+		for j, name := range header {
+			fields[strings.TrimSpace(name)] = record[j]
+		}
+
+		row := &csvRow{Line: i + 2, To: strings.TrimSpace(record[toIndex])}
+		rows[i] = row
+
+		if row.To == "" {
+			row.Err = fmt.Errorf("no recipient")
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, fields); err != nil {
+			row.Err = err
+			continue
+		}
+
+		row.Body = buf.String()
+		row.Segments = esendex.Segments(row.Body)
+	}
+
+	return rows, nil
+}
+
+func sendCSVRows(account *esendex.AccountClient, from string, rows []*csvRow, chunkSize int) {
+	var pending []*csvRow
+	for _, row := range rows {
+		if row.Err == nil {
+			pending = append(pending, row)
+		}
+	}
+
+	for start := 0; start < len(pending); start += chunkSize {
+		end := start + chunkSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		chunk := pending[start:end]
+
+		messages := make([]esendex.Message, len(chunk))
+		for i, row := range chunk {
+			messages[i] = esendex.Message{To: row.To, Body: row.Body}
+		}
+
+		var (
+			resp *esendex.SendResponse
+			err  error
+		)
+		if from != "" {
+			resp, err = account.SendFrom(from, messages)
+		} else {
+			resp, err = account.Send(messages)
+		}
+
+		if err == nil && len(resp.Messages) != len(chunk) {
+			err = fmt.Errorf("expected %d message ids, got %d", len(chunk), len(resp.Messages))
+		}
+
+		for i, row := range chunk {
+			if err != nil {
+				row.Err = err
+				continue
+			}
+
+			row.BatchID = resp.BatchID
+			row.MessageID = resp.Messages[i].ID
+		}
+
+		fmt.Fprintf(os.Stderr, "Sent %d of %d\n", end, len(pending))
+	}
+}
+
+func writeCSVResults(path string, rows []*csvRow) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"line", "to", "batch_id", "message_id", "error"})
+
+	for _, row := range rows {
+		errText := ""
+		if row.Err != nil {
+			errText = row.Err.Error()
+		}
+
+		w.Write([]string{strconv.Itoa(row.Line), row.To, row.BatchID, row.MessageID, errText})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func confirm(prompt string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
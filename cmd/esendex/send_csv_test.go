@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeCSV(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "recipients.csv")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestReadCSVRows(t *testing.T) {
+	path := writeCSV(t, "\xef\xbb\xbf\"To\", Name \n447700900123,Ann\n,Bob\n447700900124,\n")
+	tmpl := template.Must(template.New("body").Option("missingkey=error").Parse("Hi {{.Name}}"))
+
+	rows, err := readCSVRows(path, "to", tmpl)
+
+	assert := assert.New(t)
+
+	if assert.Nil(err) && assert.Len(rows, 3) {
+		assert.Equal(2, rows[0].Line)
+		assert.Equal("447700900123", rows[0].To)
+		assert.Equal("Hi Ann", rows[0].Body)
+		assert.Equal(1, rows[0].Segments)
+		assert.Nil(rows[0].Err)
+
+		assert.Equal(3, rows[1].Line)
+		assert.EqualError(rows[1].Err, "no recipient")
+
+		assert.Equal("Hi ", rows[2].Body)
+	}
+}
+
+func TestReadCSVRowsWhenTemplateFails(t *testing.T) {
+	path := writeCSV(t, "to\n447700900123\n")
+	tmpl := template.Must(template.New("body").Option("missingkey=error").Parse("Hi {{.Name}}"))
+
+	rows, err := readCSVRows(path, "to", tmpl)
+
+	if assert.Nil(t, err) && assert.Len(t, rows, 1) {
+		assert.NotNil(t, rows[0].Err)
+		assert.Empty(t, rows[0].Body)
+	}
+}
+
+func TestReadCSVRowsWithoutToColumn(t *testing.T) {
+	path := writeCSV(t, "number,name\n447700900123,Ann\n")
+	tmpl := template.Must(template.New("body").Parse("Hi"))
+
+	_, err := readCSVRows(path, "to", tmpl)
+	assert.EqualError(t, err, path+` has no "to" column`)
+
+	_, err = readCSVRows(writeCSV(t, ""), "to", tmpl)
+	assert.NotNil(t, err)
+}
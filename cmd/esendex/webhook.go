@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+	"hawx.me/code/hadfield"
+)
+
+// webhookEvent is the output for a push notification.
+type webhookEvent struct {
+	Type          string
+	ID            string
+	MessageID     string
+	AccountID     string
+	OccurredAt    time.Time
+	From          string
+	To            string
+	MessageText   string
+	FailureReason *esendex.FailureReason
+}
+
+func webhookCmd() *hadfield.Command {
+	var (
+		addr    string
+		path    string
+		forward string
+	)
+
+	cmd := &hadfield.Command{
+		Usage: "webhook [options] serve",
+		Short: "receives push notifications locally",
+		Long: `
+  Webhook serve listens for push notifications of inbound messages, delivered
+  messages and failed messages, and displays each as it arrives. To receive
+  notifications from the API the address must be reachable from the internet,
+  for example through a tunnel.
+
+  Notifications can also be forwarded as JSON to another URL, so that code
+  under development can handle them without parsing the API's XML.
+
+    --addr ADDR      # Address to listen on (default: :8080)
+    --path PATH      # Path to receive notifications at (default: /)
+    --forward URL    # POST each notification as JSON to URL
+`,
+		Run: func(cmd *hadfield.Command, args []string) {
+			args = parseInterspersed(cmd, args)
+
+			if len(args) < 1 || args[0] != "serve" {
+				log.Fatal("Require serve parameter")
+			}
+			if len(args) > 1 {
+				log.Fatalf("Unexpected arguments: %s", strings.Join(args[1:], " "))
+			}
+
+			r := &webhookReceiver{
+				forward: forward,
+				client:  &http.Client{Timeout: 10 * time.Second},
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle(path, esendex.NotificationHandler{
+				Inbound: func(n esendex.InboundMessageNotification) {
+					r.receive(webhookEvent{
+						Type:        "inbound",
+						ID:          n.ID,
+						MessageID:   n.MessageID,
+						AccountID:   n.AccountID,
+						From:        n.From,
+						To:          n.To,
+						MessageText: n.MessageText,
+					})
+				},
+				Delivered: func(n esendex.MessageDeliveredNotification) {
+					r.receive(webhookEvent{
+						Type:       "delivered",
+						ID:         n.ID,
+						MessageID:  n.MessageID,
+						AccountID:  n.AccountID,
+						OccurredAt: n.OccurredAt,
+					})
+				},
+				Failed: func(n esendex.MessageFailedNotification) {
+					r.receive(webhookEvent{
+						Type:          "failed",
+						ID:            n.ID,
+						MessageID:     n.MessageID,
+						AccountID:     n.AccountID,
+						OccurredAt:    n.OccurredAt,
+						FailureReason: n.FailureReason,
+					})
+				},
+			})
+
+			fmt.Fprintf(os.Stderr, "Listening for notifications on %s%s\n", addr, path)
+			log.Fatal(http.ListenAndServe(addr, mux))
+		},
+	}
+
+	cmd.Flag.StringVar(&addr, "addr", ":8080", "")
+	cmd.Flag.StringVar(&path, "path", "/", "")
+	cmd.Flag.StringVar(&forward, "forward", "", "")
+
+	return cmd
+}
+
+// webhookReceiver displays and forwards notifications. Notifications can arrive
+// concurrently, so they are handled one at a time to keep the output readable.
+type webhookReceiver struct {
+	mu      sync.Mutex
+	forward string
+	client  *http.Client
+}
+
+func (r *webhookReceiver) receive(event webhookEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if *output == "table" {
+		at := event.OccurredAt
+		if at.IsZero() {
+			at = time.Now()
+		}
+
+		switch event.Type {
+		case "inbound":
+			fmt.Printf("%s  inbound    %s  %s -> %s: %s\n", at.Local().Format("15:04:05"), event.MessageID, event.From, event.To, event.MessageText)
+		case "delivered":
+			fmt.Printf("%s  delivered  %s\n", at.Local().Format("15:04:05"), event.MessageID)
+		case "failed":
+			reason := ""
+			if event.FailureReason != nil {
+				reason = fmt.Sprintf("%d %s", event.FailureReason.Code, event.FailureReason.Description)
+				if event.FailureReason.Permanent {
+					reason += " (permanent)"
+				}
+			}
+			fmt.Printf("%s  failed     %s  %s\n", at.Local().Format("15:04:05"), event.MessageID, reason)
+		}
+	} else {
+		printOutput(event)
+	}
+
+	if r.forward == "" {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := writeOutput(&buf, "ndjson", event); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", event.ID, err)
+		return
+	}
+
+	resp, err := r.client.Post(r.forward, "application/json", &buf)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: could not forward: %v\n", event.ID, err)
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		fmt.Fprintf(os.Stderr, "%s: forward to %s returned %d\n", event.ID, r.forward, resp.StatusCode)
+	}
+}
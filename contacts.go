@@ -0,0 +1,234 @@
+package esendex
+
+import (
+	"encoding/xml"
+)
+
+// Contact is a contact to create or update.
+type Contact struct {
+	FirstName    string
+	LastName     string
+	QuickName    string
+	MobileNumber string
+}
+
+// ContactsResponse is a list of returned contacts along with the paging
+// information.
+type ContactsResponse struct {
+	Paging
+	Contacts []ContactResponse
+}
+
+// ContactResponse is a single contact.
+type ContactResponse struct {
+	ID               string
+	URI              string
+	AccountReference string
+	FirstName        string
+	LastName         string
+	QuickName        string
+	MobileNumber     string
+	GroupsURI        string
+}
+
+// Contacts returns a list of contacts for the account.
+func (c *AccountClient) Contacts(opts ...Option) (*ContactsResponse, error) {
+	accountOption := func(p *Params) error {
+		return p.Set("accountreference", c.reference)
+	}
+
+	req, err := c.newRequest("GET", "/v1.0/contacts", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyOptions(req, append(opts, accountOption)); err != nil {
+		return nil, err
+	}
+
+	var v contactsResponse
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &ContactsResponse{
+		Paging: Paging{
+			StartIndex: v.StartIndex,
+			Count:      v.Count,
+			TotalCount: v.TotalCount,
+		},
+		Contacts: make([]ContactResponse, len(v.Contacts)),
+	}
+
+	for i, contact := range v.Contacts {
+		response.Contacts[i] = ContactResponse{
+			ID:               contact.ID,
+			URI:              contact.URI,
+			AccountReference: contact.AccountReference,
+			FirstName:        contact.FirstName,
+			LastName:         contact.LastName,
+			QuickName:        contact.QuickName,
+			MobileNumber:     contact.MobileNumber,
+			GroupsURI:        contact.Groups.URI,
+		}
+	}
+
+	return response, nil
+}
+
+// Contact returns the contact with the given id.
+func (c *AccountClient) Contact(id string) (*ContactResponse, error) {
+	req, err := c.newRequest("GET", "/v1.0/contacts/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var v contactsResponseContact
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &ContactResponse{
+		ID:               v.ID,
+		URI:              v.URI,
+		AccountReference: v.AccountReference,
+		FirstName:        v.FirstName,
+		LastName:         v.LastName,
+		QuickName:        v.QuickName,
+		MobileNumber:     v.MobileNumber,
+		GroupsURI:        v.Groups.URI,
+	}
+
+	return response, nil
+}
+
+// CreateContacts adds a list of contacts to the account.
+func (c *AccountClient) CreateContacts(contacts []Contact) (*ContactsResponse, error) {
+	body := contactsRequest{
+		Contacts: make([]contactRequest, len(contacts)),
+	}
+
+	for i, contact := range contacts {
+		body.Contacts[i] = contactRequest{
+			AccountReference: c.reference,
+			FirstName:        contact.FirstName,
+			LastName:         contact.LastName,
+			QuickName:        contact.QuickName,
+			MobileNumber:     contact.MobileNumber,
+		}
+	}
+
+	req, err := c.newRequest("POST", "/v1.0/contacts", &body)
+	if err != nil {
+		return nil, err
+	}
+
+	var v contactsResponse
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &ContactsResponse{
+		Paging: Paging{
+			StartIndex: v.StartIndex,
+			Count:      v.Count,
+			TotalCount: v.TotalCount,
+		},
+		Contacts: make([]ContactResponse, len(v.Contacts)),
+	}
+
+	for i, contact := range v.Contacts {
+		response.Contacts[i] = ContactResponse{
+			ID:               contact.ID,
+			URI:              contact.URI,
+			AccountReference: contact.AccountReference,
+			FirstName:        contact.FirstName,
+			LastName:         contact.LastName,
+			QuickName:        contact.QuickName,
+			MobileNumber:     contact.MobileNumber,
+			GroupsURI:        contact.Groups.URI,
+		}
+	}
+
+	return response, nil
+}
+
+// UpdateContact replaces the details of the contact with the given id.
+func (c *AccountClient) UpdateContact(id string, contact Contact) (*ContactResponse, error) {
+	body := contactRequest{
+		AccountReference: c.reference,
+		FirstName:        contact.FirstName,
+		LastName:         contact.LastName,
+		QuickName:        contact.QuickName,
+		MobileNumber:     contact.MobileNumber,
+	}
+
+	req, err := c.newRequest("PUT", "/v1.0/contacts/"+id, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	var v contactsResponseContact
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &ContactResponse{
+		ID:               v.ID,
+		URI:              v.URI,
+		AccountReference: v.AccountReference,
+		FirstName:        v.FirstName,
+		LastName:         v.LastName,
+		QuickName:        v.QuickName,
+		MobileNumber:     v.MobileNumber,
+		GroupsURI:        v.Groups.URI,
+	}
+
+	return response, nil
+}
+
+// DeleteContact removes the contact with the given id.
+func (c *AccountClient) DeleteContact(id string) error {
+	req, err := c.newRequest("DELETE", "/v1.0/contacts/"+id, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(req, nil)
+	return err
+}
+
+type contactsRequest struct {
+	XMLName  xml.Name         `xml:"contacts"`
+	Contacts []contactRequest `xml:"contact"`
+}
+
+type contactRequest struct {
+	XMLName          xml.Name `xml:"contact"`
+	AccountReference string   `xml:"accountreference"`
+	FirstName        string   `xml:"firstname,omitempty"`
+	LastName         string   `xml:"lastname,omitempty"`
+	QuickName        string   `xml:"quickname,omitempty"`
+	MobileNumber     string   `xml:"mobilenumber"`
+}
+
+type contactsResponse struct {
+	XMLName    xml.Name                  `xml:"http://api.esendex.com/ns/ contacts"`
+	StartIndex int                       `xml:"startindex,attr"`
+	Count      int                       `xml:"count,attr"`
+	TotalCount int                       `xml:"totalcount,attr"`
+	Contacts   []contactsResponseContact `xml:"contact"`
+}
+
+type contactsResponseContact struct {
+	ID               string `xml:"id,attr"`
+	URI              string `xml:"uri,attr"`
+	AccountReference string `xml:"accountreference"`
+	FirstName        string `xml:"firstname"`
+	LastName         string `xml:"lastname"`
+	QuickName        string `xml:"quickname"`
+	MobileNumber     string `xml:"mobilenumber"`
+	Groups           struct {
+		URI string `xml:"uri,attr"`
+	} `xml:"groups"`
+}
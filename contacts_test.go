@@ -0,0 +1,234 @@
+package esendex
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleAccountClient_CreateContacts() {
+	accountClient := New("user@example.com", "pass").Account("EX00000")
+
+	accountClient.CreateContacts([]Contact{
+		{FirstName: "Jane", LastName: "Doe", MobileNumber: "447700900000"},
+	})
+}
+
+func TestContacts(t *testing.T) {
+	const (
+		startIndex       = 0
+		count            = 15
+		totalCount       = 30
+		id               = "contactid"
+		uri              = "http://somecontact"
+		accountReference = "EX0000000"
+		firstName        = "Jane"
+		lastName         = "Doe"
+		quickName        = "JD"
+		mobileNumber     = "447700900000"
+		groupsURI        = "http://somecontact/groups"
+	)
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<contacts startindex="`+strconv.Itoa(startIndex)+`" count="`+strconv.Itoa(count)+`" totalcount="`+strconv.Itoa(totalCount)+`" xmlns="http://api.esendex.com/ns/">
+ <contact id="`+id+`" uri="`+uri+`">
+  <firstname>`+firstName+`</firstname>
+  <lastname>`+lastName+`</lastname>
+  <quickname>`+quickName+`</quickname>
+  <mobilenumber>`+mobileNumber+`</mobilenumber>
+  <accountreference>`+accountReference+`</accountreference>
+  <groups uri="`+groupsURI+`" />
+ </contact>
+</contacts>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	result, err := client.Account(accountReference).Contacts(Page(0, 15))
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/contacts", h.Request.URL.Path)
+
+	if user, pass, ok := h.Request.BasicAuth(); assert.True(ok) {
+		assert.Equal("user", user)
+		assert.Equal("pass", pass)
+	}
+
+	query := h.Request.URL.Query()
+	assert.Equal(accountReference, query.Get("accountreference"))
+	assert.Equal("0", query.Get("startindex"))
+	assert.Equal("15", query.Get("count"))
+
+	assert.Equal(startIndex, result.StartIndex)
+	assert.Equal(count, result.Count)
+	assert.Equal(totalCount, result.TotalCount)
+
+	if assert.Equal(1, len(result.Contacts)) {
+		contact := result.Contacts[0]
+
+		assert.Equal(id, contact.ID)
+		assert.Equal(uri, contact.URI)
+		assert.Equal(accountReference, contact.AccountReference)
+		assert.Equal(firstName, contact.FirstName)
+		assert.Equal(lastName, contact.LastName)
+		assert.Equal(quickName, contact.QuickName)
+		assert.Equal(mobileNumber, contact.MobileNumber)
+		assert.Equal(groupsURI, contact.GroupsURI)
+	}
+}
+
+func TestContact(t *testing.T) {
+	const (
+		id               = "contactid"
+		uri              = "http://somecontact"
+		accountReference = "EX0000000"
+		firstName        = "Jane"
+		mobileNumber     = "447700900000"
+	)
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<contact id="`+id+`" uri="`+uri+`" xmlns="http://api.esendex.com/ns/">
+ <firstname>`+firstName+`</firstname>
+ <mobilenumber>`+mobileNumber+`</mobilenumber>
+ <accountreference>`+accountReference+`</accountreference>
+</contact>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	contact, err := client.Account(accountReference).Contact(id)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/contacts/"+id, h.Request.URL.String())
+
+	assert.Equal(id, contact.ID)
+	assert.Equal(uri, contact.URI)
+	assert.Equal(accountReference, contact.AccountReference)
+	assert.Equal(firstName, contact.FirstName)
+	assert.Equal(mobileNumber, contact.MobileNumber)
+}
+
+func TestCreateContacts(t *testing.T) {
+	const (
+		id               = "contactid"
+		uri              = "http://somecontact"
+		accountReference = "EX0000000"
+		firstName        = "Jane"
+		lastName         = "Doe"
+		mobileNumber     = "447700900000"
+	)
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<contacts startindex="0" count="1" totalcount="1" xmlns="http://api.esendex.com/ns/">
+ <contact id="`+id+`" uri="`+uri+`">
+  <firstname>`+firstName+`</firstname>
+  <lastname>`+lastName+`</lastname>
+  <mobilenumber>`+mobileNumber+`</mobilenumber>
+  <accountreference>`+accountReference+`</accountreference>
+ </contact>
+</contacts>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	result, err := client.Account(accountReference).CreateContacts([]Contact{
+		{FirstName: firstName, LastName: lastName, MobileNumber: mobileNumber},
+	})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("POST", h.Request.Method)
+	assert.Equal("/v1.0/contacts", h.Request.URL.String())
+	assert.Equal("<contacts>"+
+		"<contact>"+
+		"<accountreference>"+accountReference+"</accountreference>"+
+		"<firstname>"+firstName+"</firstname>"+
+		"<lastname>"+lastName+"</lastname>"+
+		"<mobilenumber>"+mobileNumber+"</mobilenumber>"+
+		"</contact>"+
+		"</contacts>", h.RequestBody)
+
+	if assert.Equal(1, len(result.Contacts)) {
+		assert.Equal(id, result.Contacts[0].ID)
+		assert.Equal(uri, result.Contacts[0].URI)
+	}
+}
+
+func TestUpdateContact(t *testing.T) {
+	const (
+		id               = "contactid"
+		uri              = "http://somecontact"
+		accountReference = "EX0000000"
+		quickName        = "JD"
+		mobileNumber     = "447700900000"
+	)
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<contact id="`+id+`" uri="`+uri+`" xmlns="http://api.esendex.com/ns/">
+ <quickname>`+quickName+`</quickname>
+ <mobilenumber>`+mobileNumber+`</mobilenumber>
+ <accountreference>`+accountReference+`</accountreference>
+</contact>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	contact, err := client.Account(accountReference).UpdateContact(id, Contact{
+		QuickName:    quickName,
+		MobileNumber: mobileNumber,
+	})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("PUT", h.Request.Method)
+	assert.Equal("/v1.0/contacts/"+id, h.Request.URL.String())
+	assert.Equal("<contact>"+
+		"<accountreference>"+accountReference+"</accountreference>"+
+		"<quickname>"+quickName+"</quickname>"+
+		"<mobilenumber>"+mobileNumber+"</mobilenumber>"+
+		"</contact>", h.RequestBody)
+
+	assert.Equal(id, contact.ID)
+	assert.Equal(quickName, contact.QuickName)
+}
+
+func TestDeleteContact(t *testing.T) {
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	err := client.Account("EX0000000").DeleteContact("contactid")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("DELETE", h.Request.Method)
+	assert.Equal("/v1.0/contacts/contactid", h.Request.URL.String())
+}
@@ -0,0 +1,265 @@
+package esendextest
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+)
+
+// Call is a single method call recorded by a Mock.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Mock is an in-memory implementation of esendex.AccountAPI that records every
+// call made to it.
+//
+// Each method returns the result of the matching func field if it is set.
+// Otherwise an empty response and a nil error are returned, except for the send
+// methods which return a response with a generated id for each message.
+type Mock struct {
+	SentFunc               func(opts ...esendex.Option) (*esendex.SentMessagesResponse, error)
+	ReceivedFunc           func(opts ...esendex.Option) (*esendex.ReceivedMessagesResponse, error)
+	MessageFunc            func(id string) (*esendex.MessageResponse, error)
+	BodyFunc               func(message esendex.MessageWithBody) (*esendex.MessageBody, error)
+	MarkReadFunc           func(id string) error
+	MarkUnreadFunc         func(id string) error
+	BatchesFunc            func(opts ...esendex.Option) (*esendex.BatchesResponse, error)
+	BatchFunc              func(id string) (*esendex.BatchResponse, error)
+	CancelBatchFunc        func(id string) error
+	AccountsFunc           func() (*esendex.AccountsResponse, error)
+	AccountByIDFunc        func(id string) (*esendex.AccountResponse, error)
+	AccountByReferenceFunc func(reference string) (*esendex.AccountResponse, error)
+	SendFunc               func(messages []esendex.Message) (*esendex.SendResponse, error)
+	SendFromFunc           func(from string, messages []esendex.Message) (*esendex.SendResponse, error)
+	SendAtFunc             func(sendAt time.Time, messages []esendex.Message) (*esendex.SendResponse, error)
+
+	mu      sync.Mutex
+	calls   []Call
+	batches int
+}
+
+var _ esendex.AccountAPI = (*Mock)(nil)
+
+// Calls returns the calls made so far, in order.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the calls made so far to the named method, in order.
+func (m *Mock) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// Reset forgets all recorded calls.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+}
+
+func (m *Mock) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+func optionArgs(opts []esendex.Option) []interface{} {
+	args := make([]interface{}, len(opts))
+	for i, opt := range opts {
+		args[i] = opt
+	}
+
+	return args
+}
+
+// Sent implements esendex.MessageService.
+func (m *Mock) Sent(opts ...esendex.Option) (*esendex.SentMessagesResponse, error) {
+	m.record("Sent", optionArgs(opts)...)
+
+	if m.SentFunc != nil {
+		return m.SentFunc(opts...)
+	}
+	return &esendex.SentMessagesResponse{}, nil
+}
+
+// Received implements esendex.MessageService.
+func (m *Mock) Received(opts ...esendex.Option) (*esendex.ReceivedMessagesResponse, error) {
+	m.record("Received", optionArgs(opts)...)
+
+	if m.ReceivedFunc != nil {
+		return m.ReceivedFunc(opts...)
+	}
+	return &esendex.ReceivedMessagesResponse{}, nil
+}
+
+// Message implements esendex.MessageService.
+func (m *Mock) Message(id string) (*esendex.MessageResponse, error) {
+	m.record("Message", id)
+
+	if m.MessageFunc != nil {
+		return m.MessageFunc(id)
+	}
+	return &esendex.MessageResponse{ID: id}, nil
+}
+
+// Body implements esendex.MessageService.
+func (m *Mock) Body(message esendex.MessageWithBody) (*esendex.MessageBody, error) {
+	m.record("Body", message)
+
+	if m.BodyFunc != nil {
+		return m.BodyFunc(message)
+	}
+	return &esendex.MessageBody{}, nil
+}
+
+// MarkRead implements esendex.MessageService.
+func (m *Mock) MarkRead(id string) error {
+	m.record("MarkRead", id)
+
+	if m.MarkReadFunc != nil {
+		return m.MarkReadFunc(id)
+	}
+	return nil
+}
+
+// MarkUnread implements esendex.MessageService.
+func (m *Mock) MarkUnread(id string) error {
+	m.record("MarkUnread", id)
+
+	if m.MarkUnreadFunc != nil {
+		return m.MarkUnreadFunc(id)
+	}
+	return nil
+}
+
+// Batches implements esendex.BatchService.
+func (m *Mock) Batches(opts ...esendex.Option) (*esendex.BatchesResponse, error) {
+	m.record("Batches", optionArgs(opts)...)
+
+	if m.BatchesFunc != nil {
+		return m.BatchesFunc(opts...)
+	}
+	return &esendex.BatchesResponse{}, nil
+}
+
+// Batch implements esendex.BatchService.
+func (m *Mock) Batch(id string) (*esendex.BatchResponse, error) {
+	m.record("Batch", id)
+
+	if m.BatchFunc != nil {
+		return m.BatchFunc(id)
+	}
+	return &esendex.BatchResponse{ID: id, Status: map[string]int{}}, nil
+}
+
+// CancelBatch implements esendex.BatchService.
+func (m *Mock) CancelBatch(id string) error {
+	m.record("CancelBatch", id)
+
+	if m.CancelBatchFunc != nil {
+		return m.CancelBatchFunc(id)
+	}
+	return nil
+}
+
+// Accounts implements esendex.AccountsService.
+func (m *Mock) Accounts() (*esendex.AccountsResponse, error) {
+	m.record("Accounts")
+
+	if m.AccountsFunc != nil {
+		return m.AccountsFunc()
+	}
+	return &esendex.AccountsResponse{}, nil
+}
+
+// AccountByID implements esendex.AccountsService.
+func (m *Mock) AccountByID(id string) (*esendex.AccountResponse, error) {
+	m.record("AccountByID", id)
+
+	if m.AccountByIDFunc != nil {
+		return m.AccountByIDFunc(id)
+	}
+	return &esendex.AccountResponse{ID: id}, nil
+}
+
+// AccountByReference implements esendex.AccountsService.
+func (m *Mock) AccountByReference(reference string) (*esendex.AccountResponse, error) {
+	m.record("AccountByReference", reference)
+
+	if m.AccountByReferenceFunc != nil {
+		return m.AccountByReferenceFunc(reference)
+	}
+	return &esendex.AccountResponse{Reference: reference}, nil
+}
+
+// Send implements esendex.SendService.
+func (m *Mock) Send(messages []esendex.Message) (*esendex.SendResponse, error) {
+	m.record("Send", messages)
+
+	if m.SendFunc != nil {
+		return m.SendFunc(messages)
+	}
+	return m.sendResponse(messages), nil
+}
+
+// SendFrom implements esendex.SendService.
+func (m *Mock) SendFrom(from string, messages []esendex.Message) (*esendex.SendResponse, error) {
+	m.record("SendFrom", from, messages)
+
+	if m.SendFromFunc != nil {
+		return m.SendFromFunc(from, messages)
+	}
+	return m.sendResponse(messages), nil
+}
+
+// SendAt implements esendex.SendService.
+func (m *Mock) SendAt(sendAt time.Time, messages []esendex.Message) (*esendex.SendResponse, error) {
+	m.record("SendAt", sendAt, messages)
+
+	if m.SendAtFunc != nil {
+		return m.SendAtFunc(sendAt, messages)
+	}
+	return m.sendResponse(messages), nil
+}
+
+func (m *Mock) sendResponse(messages []esendex.Message) *esendex.SendResponse {
+	m.mu.Lock()
+	m.batches++
+	batchID := "batch-" + strconv.Itoa(m.batches)
+	m.mu.Unlock()
+
+	response := &esendex.SendResponse{
+		BatchID:  batchID,
+		Messages: make([]esendex.SendResponseMessage, len(messages)),
+	}
+
+	for i := range messages {
+		id := batchID + "-message-" + strconv.Itoa(i+1)
+
+		response.Messages[i] = esendex.SendResponseMessage{
+			ID:  id,
+			URI: "/v1.0/messageheaders/" + id,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,112 @@
+package esendextest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func notifyCustomer(api esendex.AccountAPI, to string) (string, error) {
+	resp, err := api.Send([]esendex.Message{{To: to, Body: "Your order has shipped"}})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Messages[0].ID, nil
+}
+
+func TestMockRecordsCalls(t *testing.T) {
+	mock := &Mock{}
+	assert := assert.New(t)
+
+	id, err := notifyCustomer(mock, "447700900001")
+	assert.Nil(err)
+	assert.Equal("batch-1-message-1", id)
+
+	sendAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp, err := mock.SendAt(sendAt, []esendex.Message{{To: "1"}, {To: "2"}})
+	if assert.Nil(err) {
+		assert.Equal("batch-2", resp.BatchID)
+		assert.Len(resp.Messages, 2)
+	}
+
+	mock.Batch("batchid")
+
+	assert.Equal([]Call{
+		{Method: "Send", Args: []interface{}{[]esendex.Message{{To: "447700900001", Body: "Your order has shipped"}}}},
+		{Method: "SendAt", Args: []interface{}{sendAt, []esendex.Message{{To: "1"}, {To: "2"}}}},
+		{Method: "Batch", Args: []interface{}{"batchid"}},
+	}, mock.Calls())
+
+	assert.Len(mock.CallsTo("Send"), 1)
+	assert.Len(mock.CallsTo("Message"), 0)
+
+	mock.Reset()
+	assert.Empty(mock.Calls())
+}
+
+func TestMockFuncs(t *testing.T) {
+	sendErr := errors.New("no credit")
+
+	mock := &Mock{
+		SendFunc: func(messages []esendex.Message) (*esendex.SendResponse, error) {
+			return nil, sendErr
+		},
+		AccountsFunc: func() (*esendex.AccountsResponse, error) {
+			return &esendex.AccountsResponse{
+				Accounts: []esendex.AccountResponse{{Reference: "EX0000000"}},
+			}, nil
+		},
+	}
+
+	assert := assert.New(t)
+
+	_, err := notifyCustomer(mock, "447700900001")
+	assert.Equal(sendErr, err)
+
+	accounts, err := mock.Accounts()
+	if assert.Nil(err) {
+		assert.Equal("EX0000000", accounts.Accounts[0].Reference)
+	}
+
+	sent, err := mock.Sent(esendex.Page(0, 10))
+	if assert.Nil(err) {
+		assert.Empty(sent.Messages)
+	}
+
+	if calls := mock.CallsTo("Sent"); assert.Len(calls, 1) {
+		assert.Len(calls[0].Args, 1)
+	}
+}
+
+func TestMockOptions(t *testing.T) {
+	var params esendex.Params
+
+	mock := &Mock{
+		SentFunc: func(opts ...esendex.Option) (*esendex.SentMessagesResponse, error) {
+			for _, opt := range opts {
+				if err := opt(&params); err != nil {
+					return nil, err
+				}
+			}
+
+			return &esendex.SentMessagesResponse{}, nil
+		},
+	}
+
+	assert := assert.New(t)
+
+	_, err := mock.Sent(esendex.Page(20, 10), esendex.Status("Delivered"))
+	if assert.Nil(err) {
+		assert.Equal("20", params.Get("startindex"))
+		assert.Equal("10", params.Get("count"))
+		assert.Equal("Delivered", params.Get("status"))
+		assert.Equal("", params.Get("to"))
+	}
+
+	_, err = mock.Sent(esendex.Status("Failed"))
+	assert.Equal(esendex.OptionError{Param: "status", Reason: "is given more than once"}, err)
+}
@@ -0,0 +1,197 @@
+package esendextest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+)
+
+// Outcome scripts what happens to a message after it is submitted.
+type Outcome struct {
+	// Delay is how long after submission the message is delivered or fails.
+	Delay time.Duration
+
+	// Failure, if set, causes the message to fail with the reason instead of
+	// being delivered. Set Permanent to distinguish permanent failures from
+	// temporary ones.
+	Failure *esendex.FailureReason
+}
+
+// Delivered is an Outcome that delivers messages immediately.
+var Delivered = Outcome{}
+
+// TemporaryFailure returns an Outcome that fails messages after the delay with a
+// temporary failure reason.
+func TemporaryFailure(delay time.Duration, code int, description string) Outcome {
+	return Outcome{
+		Delay:   delay,
+		Failure: &esendex.FailureReason{Code: code, Description: description},
+	}
+}
+
+// PermanentFailure returns an Outcome that fails messages after the delay with a
+// permanent failure reason.
+func PermanentFailure(delay time.Duration, code int, description string) Outcome {
+	return Outcome{
+		Delay:   delay,
+		Failure: &esendex.FailureReason{Code: code, Description: description, Permanent: true},
+	}
+}
+
+// SetOutcome scripts the outcome of messages subsequently sent to the number.
+// Messages sent to numbers without an outcome use DefaultOutcome, or if that is
+// nil remain Submitted until moved on with Advance or SetBatchStatus.
+func (s *Server) SetOutcome(number string, outcome Outcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.outcomes == nil {
+		s.outcomes = map[string]Outcome{}
+	}
+
+	s.outcomes[number] = outcome
+}
+
+func (s *Server) outcomeFor(number string) *Outcome {
+	if outcome, ok := s.outcomes[number]; ok {
+		return &outcome
+	}
+
+	return s.DefaultOutcome
+}
+
+// Tick applies any scripted outcomes and releases any scheduled batches that
+// are due, sending push notifications for the changes if NotifyURL is set.
+//
+// The fake also ticks whenever it handles a request, so Tick only needs to be
+// called to observe changes through notifications, or after changing Now.
+func (s *Server) Tick() {
+	s.mu.Lock()
+	notifications := s.tick()
+	s.mu.Unlock()
+
+	s.notify(notifications)
+}
+
+// tick must be called with s.mu held. It returns the notifications to send
+// once the lock is released.
+func (s *Server) tick() []interface{} {
+	now := s.Now()
+
+	var notifications []interface{}
+
+	for _, b := range s.batches {
+		for _, m := range b.Messages {
+			if m.Status == StatusScheduled && !b.SendAt.After(now) {
+				s.setStatusAt(m, StatusSubmitted, b.SendAt)
+				m.releasedAt = b.SendAt
+			}
+
+			if (m.Status != StatusSubmitted && m.Status != StatusSent) || m.outcome == nil {
+				continue
+			}
+
+			at := m.releasedAt.Add(m.outcome.Delay)
+			if at.After(now) {
+				continue
+			}
+
+			if m.outcome.Failure != nil {
+				failure := *m.outcome.Failure
+				s.setStatusAt(m, StatusFailed, at)
+				m.FailureReason = &failure
+
+				notifications = append(notifications, messageFailedXML{
+					ID:         newID(),
+					MessageID:  m.ID,
+					AccountID:  s.accountID(m.AccountReference),
+					OccurredAt: formatTime(at),
+					FailureReason: &notificationFailureXML{
+						Code:        failure.Code,
+						Description: failure.Description,
+						Permanent:   failure.Permanent,
+					},
+				})
+			} else {
+				s.setStatusAt(m, StatusDelivered, at)
+
+				notifications = append(notifications, messageDeliveredXML{
+					ID:         newID(),
+					MessageID:  m.ID,
+					AccountID:  s.accountID(m.AccountReference),
+					OccurredAt: formatTime(at),
+				})
+			}
+		}
+	}
+
+	return notifications
+}
+
+func (s *Server) accountID(reference string) string {
+	if account := s.account(reference); account != nil {
+		return account.ID
+	}
+
+	return ""
+}
+
+// notify posts each notification to NotifyURL. It must not be called with s.mu
+// held, as the receiver may make requests back to the fake.
+func (s *Server) notify(notifications []interface{}) {
+	if s.NotifyURL == "" {
+		return
+	}
+
+	for _, notification := range notifications {
+		data, err := xml.Marshal(notification)
+		if err != nil {
+			continue
+		}
+
+		resp, err := http.Post(s.NotifyURL, "application/xml", bytes.NewReader(append([]byte(xml.Header), data...)))
+		if err != nil {
+			if s.NotifyError != nil {
+				s.NotifyError(err)
+			}
+			continue
+		}
+		resp.Body.Close()
+	}
+}
+
+type inboundMessageXML struct {
+	XMLName     xml.Name `xml:"InboundMessage"`
+	ID          string   `xml:"Id"`
+	MessageID   string   `xml:"MessageId"`
+	AccountID   string   `xml:"AccountId"`
+	MessageText string   `xml:"MessageText"`
+	From        string   `xml:"From"`
+	To          string   `xml:"To"`
+}
+
+type messageDeliveredXML struct {
+	XMLName    xml.Name `xml:"MessageDelivered"`
+	ID         string   `xml:"Id"`
+	MessageID  string   `xml:"MessageId"`
+	AccountID  string   `xml:"AccountId"`
+	OccurredAt string   `xml:"OccurredAt"`
+}
+
+type messageFailedXML struct {
+	XMLName       xml.Name                `xml:"MessageFailed"`
+	ID            string                  `xml:"Id"`
+	MessageID     string                  `xml:"MessageId"`
+	AccountID     string                  `xml:"AccountId"`
+	OccurredAt    string                  `xml:"OccurredAt"`
+	FailureReason *notificationFailureXML `xml:"FailureReason"`
+}
+
+type notificationFailureXML struct {
+	Code        int    `xml:"Code"`
+	Description string `xml:"Description"`
+	Permanent   bool   `xml:"PermanentFailure"`
+}
@@ -0,0 +1,241 @@
+package esendextest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type notifications struct {
+	mu        sync.Mutex
+	inbound   []esendex.InboundMessageNotification
+	delivered []esendex.MessageDeliveredNotification
+	failed    []esendex.MessageFailedNotification
+}
+
+func (n *notifications) handler() http.Handler {
+	return esendex.NotificationHandler{
+		Inbound: func(v esendex.InboundMessageNotification) {
+			n.mu.Lock()
+			n.inbound = append(n.inbound, v)
+			n.mu.Unlock()
+		},
+		Delivered: func(v esendex.MessageDeliveredNotification) {
+			n.mu.Lock()
+			n.delivered = append(n.delivered, v)
+			n.mu.Unlock()
+		},
+		Failed: func(v esendex.MessageFailedNotification) {
+			n.mu.Lock()
+			n.failed = append(n.failed, v)
+			n.mu.Unlock()
+		},
+	}
+}
+
+func TestOutcomes(t *testing.T) {
+	c := &clock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	s := newTestServer()
+	defer s.Close()
+	s.Now = c.Now
+
+	s.SetOutcome("447700900001", Outcome{Delay: time.Minute})
+	s.SetOutcome("447700900002", TemporaryFailure(2*time.Minute, 6, "Network issue"))
+	s.SetOutcome("447700900003", PermanentFailure(0, 14, "Unknown subscriber"))
+
+	client := s.Client()
+	assert := assert.New(t)
+
+	resp, err := client.Account("EX0000000").Send([]esendex.Message{
+		{To: "447700900001", Body: "Delayed"},
+		{To: "447700900002", Body: "Temporary"},
+		{To: "447700900003", Body: "Permanent"},
+		{To: "447700900004", Body: "Unscripted"},
+	})
+	if !assert.Nil(err) {
+		return
+	}
+
+	status := func(i int) *esendex.MessageResponse {
+		message, err := client.Message(resp.Messages[i].ID)
+		assert.Nil(err)
+		return message
+	}
+
+	if m := status(2); assert.Equal(StatusFailed, m.Status) && assert.NotNil(m.FailureReason) {
+		assert.Equal(&esendex.FailureReason{Code: 14, Description: "Unknown subscriber", Permanent: true}, m.FailureReason)
+	}
+	assert.Equal(StatusSubmitted, status(0).Status)
+	assert.Equal(StatusSubmitted, status(1).Status)
+
+	c.Add(time.Minute)
+
+	if m := status(0); assert.Equal(StatusDelivered, m.Status) {
+		assert.Equal(c.Now(), m.DeliveredAt)
+	}
+	assert.Equal(StatusSubmitted, status(1).Status)
+
+	c.Add(time.Minute)
+
+	if m := status(1); assert.Equal(StatusFailed, m.Status) && assert.NotNil(m.FailureReason) {
+		assert.Equal(6, m.FailureReason.Code)
+		assert.False(m.FailureReason.Permanent)
+	}
+
+	c.Add(time.Hour)
+	assert.Equal(StatusSubmitted, status(3).Status)
+
+	batch, err := client.Batch(resp.BatchID)
+	if assert.Nil(err) {
+		assert.Equal(map[string]int{"delivered": 1, "failed": 2, "submitted": 1}, batch.Status)
+	}
+}
+
+func TestDefaultOutcome(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	s.DefaultOutcome = &Delivered
+
+	client := s.Client()
+	assert := assert.New(t)
+
+	resp, err := client.Account("EX0000000").Send([]esendex.Message{{To: "447700900001", Body: "Hi"}})
+	if assert.Nil(err) {
+		message, err := client.Message(resp.Messages[0].ID)
+		if assert.Nil(err) {
+			assert.Equal(StatusDelivered, message.Status)
+		}
+	}
+}
+
+func TestScheduledRelease(t *testing.T) {
+	c := &clock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	s := newTestServer()
+	defer s.Close()
+	s.Now = c.Now
+	s.SetOutcome("447700900001", Outcome{Delay: 10 * time.Second})
+
+	client := s.Client()
+	account := client.Account("EX0000000")
+	assert := assert.New(t)
+
+	sendAt := c.Now().Add(time.Hour)
+
+	resp, err := account.SendAt(sendAt, []esendex.Message{{To: "447700900001", Body: "Later"}})
+	if !assert.Nil(err) {
+		return
+	}
+
+	cancelled, err := account.SendAt(sendAt, []esendex.Message{{To: "447700900001", Body: "Never"}})
+	if !assert.Nil(err) {
+		return
+	}
+
+	batch, err := client.Batch(resp.BatchID)
+	if assert.Nil(err) {
+		assert.Equal(map[string]int{"scheduled": 1}, batch.Status)
+	}
+
+	assert.Nil(client.CancelBatch(cancelled.BatchID))
+
+	c.Add(time.Hour)
+
+	if m, err := client.Message(resp.Messages[0].ID); assert.Nil(err) {
+		assert.Equal(StatusSubmitted, m.Status)
+	}
+
+	c.Add(10 * time.Second)
+
+	if m, err := client.Message(resp.Messages[0].ID); assert.Nil(err) {
+		assert.Equal(StatusDelivered, m.Status)
+		assert.Equal(sendAt.Add(10*time.Second), m.DeliveredAt)
+	}
+
+	if m, err := client.Message(cancelled.Messages[0].ID); assert.Nil(err) {
+		assert.Equal(StatusCancelled, m.Status)
+	}
+
+	assert.NotNil(client.CancelBatch(resp.BatchID))
+}
+
+func TestNotifications(t *testing.T) {
+	c := &clock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	var n notifications
+	webhook := httptest.NewServer(n.handler())
+	defer webhook.Close()
+
+	s := newTestServer()
+	defer s.Close()
+	s.Now = c.Now
+	s.NotifyURL = webhook.URL
+	s.NotifyError = func(err error) { t.Error(err) }
+
+	s.SetOutcome("447700900001", Outcome{Delay: time.Minute})
+	s.SetOutcome("447700900002", PermanentFailure(time.Minute, 14, "Unknown subscriber"))
+
+	assert := assert.New(t)
+
+	id, err := s.Receive("EX0000000", "447700900123", "Hello")
+	assert.Nil(err)
+
+	if assert.Len(n.inbound, 1) {
+		assert.Equal(esendex.InboundMessageNotification{
+			ID:          n.inbound[0].ID,
+			MessageID:   id,
+			AccountID:   "accountid",
+			MessageText: "Hello",
+			From:        "447700900123",
+			To:          "447700900000",
+		}, n.inbound[0])
+	}
+
+	resp, err := s.Client().Account("EX0000000").Send([]esendex.Message{
+		{To: "447700900001", Body: "Delivered"},
+		{To: "447700900002", Body: "Failed"},
+	})
+	if !assert.Nil(err) {
+		return
+	}
+
+	c.Add(time.Minute)
+	s.Tick()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if assert.Len(n.delivered, 1) {
+		assert.Equal(resp.Messages[0].ID, n.delivered[0].MessageID)
+		assert.Equal("accountid", n.delivered[0].AccountID)
+		assert.Equal(c.Now(), n.delivered[0].OccurredAt)
+	}
+
+	if assert.Len(n.failed, 1) {
+		assert.Equal(resp.Messages[1].ID, n.failed[0].MessageID)
+		assert.Equal(&esendex.FailureReason{Code: 14, Description: "Unknown subscriber", Permanent: true}, n.failed[0].FailureReason)
+	}
+}
@@ -0,0 +1,829 @@
+// Package esendextest provides an in-process fake of the Esendex REST API for
+// use in tests.
+//
+// The fake keeps state between requests: messages dispatched through it appear
+// in the sent message headers and batches, batches can be moved through their
+// statuses on demand, and inbound messages can be injected into an account's
+// inbox. Delivery can be scripted per recipient with SetOutcome, and push
+// notifications sent to NotifyURL as messages are received, delivered or fail.
+//
+// For tests that do not need HTTP at all, Mock implements esendex.AccountAPI in
+// memory and records the calls made to it.
+package esendextest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+)
+
+// Message statuses used by the fake.
+const (
+	StatusScheduled = "Scheduled"
+	StatusSubmitted = "Submitted"
+	StatusSent      = "Sent"
+	StatusDelivered = "Delivered"
+	StatusFailed    = "Failed"
+	StatusCancelled = "Cancelled"
+	StatusRead      = "Read"
+	StatusUnread    = "Unread"
+)
+
+const defaultPageSize = 15
+
+// ErrNotFound is returned when a message, batch or account does not exist.
+var ErrNotFound = errors.New("esendextest: not found")
+
+// Account is an account known to the fake.
+type Account struct {
+	ID                string
+	Reference         string
+	Label             string
+	Address           string
+	Type              string
+	MessagesRemaining int
+	ExpiresOn         time.Time
+	Role              string
+}
+
+// Server is a fake Esendex REST API backed by an httptest.Server.
+type Server struct {
+	// URL is the base URL of the fake, of the form http://ipaddr:port with no
+	// trailing slash.
+	URL string
+
+	// Username and Password are the credentials clients must use. If Username is
+	// empty any credentials are accepted. Clients may also use a session created
+	// with these credentials.
+	Username string
+	Password string
+
+	// Now returns the current time, it defaults to time.Now.
+	Now func() time.Time
+
+	// DefaultOutcome is used for messages sent to numbers that have no outcome
+	// set with SetOutcome.
+	DefaultOutcome *Outcome
+
+	// NotifyURL, if set, is sent push notifications when messages are received,
+	// delivered or fail. NotifyError is called with any error posting to it.
+	NotifyURL   string
+	NotifyError func(error)
+
+	server *httptest.Server
+
+	mu       sync.Mutex
+	accounts []*Account
+	messages []*message
+	batches  []*batch
+	outcomes map[string]Outcome
+	sessions map[string]string
+}
+
+type message struct {
+	ID               string
+	AccountReference string
+	BatchID          string
+	Direction        string
+	Type             esendex.MessageType
+	To               string
+	From             string
+	Body             string
+	CharacterSet     string
+	Status           string
+	Username         string
+	Parts            int
+	SubmittedAt      time.Time
+	LastStatusAt     time.Time
+	ReceivedAt       time.Time
+	SentAt           time.Time
+	DeliveredAt      time.Time
+	ReadAt           time.Time
+	ReadBy           string
+	FailureReason    *esendex.FailureReason
+
+	outcome    *Outcome
+	releasedAt time.Time
+}
+
+type batch struct {
+	ID               string
+	AccountReference string
+	Name             string
+	CreatedBy        string
+	CreatedAt        time.Time
+	SendAt           time.Time
+	Messages         []*message
+}
+
+// NewServer starts and returns a new fake. The caller should call Close when
+// finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{Now: time.Now}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+
+	return s
+}
+
+// NewServerAt starts and returns a new fake listening on addr, such as
+// "localhost:8080", so that it can be used outside of tests. The caller should
+// call Close when finished, to shut it down.
+func NewServerAt(addr string) (*Server, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{Now: time.Now}
+	s.server = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	s.server.Listener.Close()
+	s.server.Listener = l
+	s.server.Start()
+	s.URL = s.server.URL
+
+	return s, nil
+}
+
+// Close shuts down the fake.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns an esendex.Client configured to make requests to the fake.
+func (s *Server) Client() *esendex.Client {
+	user, pass := s.Username, s.Password
+	if user == "" {
+		user, pass = "user", "pass"
+	}
+
+	client := esendex.New(user, pass)
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	return client
+}
+
+// AddAccount adds an account to the fake. If the ID is empty one is generated.
+func (s *Server) AddAccount(account Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if account.ID == "" {
+		account.ID = newID()
+	}
+
+	s.accounts = append(s.accounts, &account)
+}
+
+// Receive injects an inbound message from the number into the inbox of the
+// account, returning the id of the new message.
+func (s *Server) Receive(accountReference, from, body string) (string, error) {
+	s.mu.Lock()
+
+	account := s.account(accountReference)
+	if account == nil {
+		s.mu.Unlock()
+		return "", ErrNotFound
+	}
+
+	now := s.Now()
+
+	m := &message{
+		ID:               newID(),
+		AccountReference: account.Reference,
+		Direction:        "IN",
+		Type:             esendex.SMS,
+		To:               account.Address,
+		From:             from,
+		Body:             body,
+		CharacterSet:     "GSM",
+		Status:           StatusUnread,
+		Parts:            esendex.Segments(body),
+		LastStatusAt:     now,
+		ReceivedAt:       now,
+	}
+
+	s.messages = append(s.messages, m)
+	s.mu.Unlock()
+
+	s.notify([]interface{}{inboundMessageXML{
+		ID:          newID(),
+		MessageID:   m.ID,
+		AccountID:   account.ID,
+		MessageText: m.Body,
+		From:        m.From,
+		To:          m.To,
+	}})
+
+	return m.ID, nil
+}
+
+// SetMessageStatus sets the status of a single message.
+func (s *Server) SetMessageStatus(id, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.message(id)
+	if m == nil {
+		return ErrNotFound
+	}
+
+	s.setStatus(m, status)
+	return nil
+}
+
+// SetBatchStatus sets the status of every message in a batch.
+func (s *Server) SetBatchStatus(id, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.batch(id)
+	if b == nil {
+		return ErrNotFound
+	}
+
+	for _, m := range b.Messages {
+		s.setStatus(m, status)
+	}
+
+	return nil
+}
+
+// Advance moves every message in a batch on to its next status, progressing
+// from Scheduled to Submitted to Sent to Delivered. Messages that have failed,
+// been cancelled or been delivered are unchanged.
+func (s *Server) Advance(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.batch(id)
+	if b == nil {
+		return ErrNotFound
+	}
+
+	for _, m := range b.Messages {
+		switch m.Status {
+		case StatusScheduled:
+			s.setStatus(m, StatusSubmitted)
+		case StatusSubmitted:
+			s.setStatus(m, StatusSent)
+		case StatusSent:
+			s.setStatus(m, StatusDelivered)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) setStatus(m *message, status string) {
+	s.setStatusAt(m, status, s.Now())
+}
+
+func (s *Server) setStatusAt(m *message, status string, now time.Time) {
+	m.Status = status
+	m.LastStatusAt = now
+
+	switch status {
+	case StatusSent:
+		m.SentAt = now
+	case StatusDelivered:
+		if m.SentAt.IsZero() {
+			m.SentAt = now
+		}
+		m.DeliveredAt = now
+	}
+}
+
+func (s *Server) account(reference string) *Account {
+	for _, a := range s.accounts {
+		if strings.EqualFold(a.Reference, reference) {
+			return a
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) message(id string) *message {
+	for _, m := range s.messages {
+		if m.ID == id {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) batch(id string) *batch {
+	for _, b := range s.batches {
+		if b.ID == id {
+			return b
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.authenticate(r)
+	if !ok {
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	notifications := s.tick()
+	s.route(w, r, user)
+	notifications = append(notifications, s.tick()...)
+	s.mu.Unlock()
+
+	go s.notify(notifications)
+}
+
+// authenticate returns the username for the request, which may use either
+// credentials or a session id. It must be called with s.mu held.
+func (s *Server) authenticate(r *http.Request) (string, bool) {
+	if user, pass, ok := r.BasicAuth(); ok {
+		return user, s.Username == "" || (user == s.Username && pass == s.Password)
+	}
+
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Basic ") {
+		id, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "Basic "))
+		if err == nil {
+			if user, ok := s.sessions[string(id)]; ok {
+				return user, true
+			}
+		}
+	}
+
+	return "", s.Username == ""
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, user string) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.Method == "POST" && match(path, "v1.0", "session", "constructor"):
+		s.postSessionConstructor(w, r, user)
+	case r.Method == "GET" && match(path, "v1.0", "accounts"):
+		s.getAccounts(w, r)
+	case r.Method == "GET" && match(path, "v1.0", "accounts", "*"):
+		s.getAccount(w, r, path[2])
+	case r.Method == "POST" && match(path, "v1.0", "messagedispatcher"):
+		s.postMessageDispatcher(w, r, user)
+	case r.Method == "GET" && match(path, "v1.0", "messageheaders"):
+		s.getMessageHeaders(w, r)
+	case r.Method == "GET" && match(path, "v1.0", "messageheaders", "*"):
+		s.getMessageHeader(w, r, path[2])
+	case r.Method == "GET" && match(path, "v1.0", "messageheaders", "*", "body"):
+		s.getMessageBody(w, r, path[2])
+	case r.Method == "GET" && match(path, "v1.0", "inbox", "messages"):
+		s.getInbox(w, r, "")
+	case r.Method == "GET" && match(path, "v1.0", "inbox", "*", "messages"):
+		s.getInbox(w, r, path[2])
+	case r.Method == "PUT" && match(path, "v1.0", "inbox", "messages", "*"):
+		s.putInboxMessage(w, r, path[3], user)
+	case r.Method == "GET" && match(path, "v1.1", "messagebatches"):
+		s.getMessageBatches(w, r)
+	case r.Method == "GET" && match(path, "v1.1", "messagebatches", "*"):
+		s.getMessageBatch(w, r, path[2])
+	case r.Method == "DELETE" && match(path, "v1.1", "messagebatches", "*", "schedule"):
+		s.deleteMessageBatchSchedule(w, r, path[2])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// match reports whether the path parts equal the pattern, where "*" matches any
+// single part.
+func match(parts []string, pattern ...string) bool {
+	if len(parts) != len(pattern) {
+		return false
+	}
+
+	for i, p := range pattern {
+		if p != "*" && !strings.EqualFold(p, parts[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Server) getAccounts(w http.ResponseWriter, r *http.Request) {
+	v := accountsXML{Accounts: make([]accountXML, len(s.accounts))}
+
+	for i, account := range s.accounts {
+		v.Accounts[i] = s.accountXML(account)
+	}
+
+	writeXML(w, "accounts", v)
+}
+
+func (s *Server) getAccount(w http.ResponseWriter, r *http.Request, id string) {
+	for _, account := range s.accounts {
+		if account.ID == id {
+			writeXML(w, "account", s.accountXML(account))
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (s *Server) postSessionConstructor(w http.ResponseWriter, r *http.Request, user string) {
+	if _, _, ok := r.BasicAuth(); !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if s.sessions == nil {
+		s.sessions = map[string]string{}
+	}
+
+	id := newID()
+	s.sessions[id] = user
+
+	writeXML(w, "session", sessionXML{ID: id})
+}
+
+func (s *Server) postMessageDispatcher(w http.ResponseWriter, r *http.Request, user string) {
+	var req dispatchRequestXML
+	if err := decodeXML(r, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	account := s.account(req.AccountReference)
+	if account == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	now := s.Now()
+
+	b := &batch{
+		ID:               newID(),
+		AccountReference: account.Reference,
+		CreatedBy:        user,
+		CreatedAt:        now,
+	}
+
+	status := StatusSubmitted
+	if req.SendAt != nil && req.SendAt.After(now) {
+		b.SendAt = *req.SendAt
+		status = StatusScheduled
+	}
+
+	for _, rm := range req.Messages {
+		if rm.To == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	for _, rm := range req.Messages {
+		from := rm.From
+		if from == "" {
+			from = req.From
+		}
+		if from == "" {
+			from = account.Address
+		}
+
+		messageType := esendex.MessageType(rm.Type)
+		if messageType == "" {
+			messageType = esendex.SMS
+		}
+
+		m := &message{
+			ID:               newID(),
+			AccountReference: account.Reference,
+			BatchID:          b.ID,
+			Direction:        "OUT",
+			Type:             messageType,
+			To:               rm.To,
+			From:             from,
+			Body:             rm.Body,
+			CharacterSet:     rm.CharacterSet,
+			Status:           status,
+			Username:         user,
+			Parts:            esendex.Segments(rm.Body),
+			SubmittedAt:      now,
+			LastStatusAt:     now,
+			outcome:          s.outcomeFor(rm.To),
+			releasedAt:       now,
+		}
+
+		if m.CharacterSet == "" {
+			m.CharacterSet = "GSM"
+		}
+
+		account.MessagesRemaining -= m.Parts
+		b.Messages = append(b.Messages, m)
+		s.messages = append(s.messages, m)
+	}
+
+	s.batches = append(s.batches, b)
+
+	v := dispatchResponseXML{
+		BatchID:  b.ID,
+		Messages: make([]linkXML, len(b.Messages)),
+	}
+
+	for i, m := range b.Messages {
+		v.Messages[i] = linkXML{ID: m.ID, URI: s.messageURI(m)}
+	}
+
+	writeXML(w, "messageheaders", v)
+}
+
+func (s *Server) getMessageHeaders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	start, finish := between(q)
+	reference := q.Get("accountReference")
+
+	var matched []*message
+	for _, m := range s.messages {
+		if m.Direction != "OUT" {
+			continue
+		}
+		if reference != "" && !strings.EqualFold(m.AccountReference, reference) {
+			continue
+		}
+		if !within(m.SubmittedAt, start, finish) || !matches(q, m) {
+			continue
+		}
+
+		matched = append(matched, m)
+	}
+
+	sortMessages(matched, q.Get("sortorder"), func(m *message) time.Time { return m.SubmittedAt })
+
+	startIndex, count := page(q)
+	v := messageHeadersXML{
+		StartIndex: startIndex,
+		TotalCount: len(matched),
+	}
+
+	for _, m := range paginate(matched, startIndex, count) {
+		v.Messages = append(v.Messages, s.messageHeaderXML(m))
+	}
+	v.Count = len(v.Messages)
+
+	writeXML(w, "messageheaders", v)
+}
+
+func (s *Server) getMessageHeader(w http.ResponseWriter, r *http.Request, id string) {
+	m := s.message(id)
+	if m == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeXML(w, "messageheader", s.messageHeaderXML(m))
+}
+
+func (s *Server) getMessageBody(w http.ResponseWriter, r *http.Request, id string) {
+	m := s.message(id)
+	if m == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeXML(w, "messagebody", messageBodyXML{
+		BodyText:     m.Body,
+		CharacterSet: m.CharacterSet,
+	})
+}
+
+func (s *Server) getInbox(w http.ResponseWriter, r *http.Request, reference string) {
+	if reference != "" && s.account(reference) == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	q := r.URL.Query()
+	start, finish := between(q)
+
+	var matched []*message
+	for _, m := range s.messages {
+		if m.Direction != "IN" {
+			continue
+		}
+		if reference != "" && !strings.EqualFold(m.AccountReference, reference) {
+			continue
+		}
+		if !within(m.ReceivedAt, start, finish) || !matches(q, m) {
+			continue
+		}
+
+		matched = append(matched, m)
+	}
+
+	sortMessages(matched, q.Get("sortorder"), func(m *message) time.Time { return m.ReceivedAt })
+
+	startIndex, count := page(q)
+	v := messageHeadersXML{
+		StartIndex: startIndex,
+		TotalCount: len(matched),
+	}
+
+	for _, m := range paginate(matched, startIndex, count) {
+		v.Messages = append(v.Messages, s.messageHeaderXML(m))
+	}
+	v.Count = len(v.Messages)
+
+	writeXML(w, "messageheaders", v)
+}
+
+func (s *Server) putInboxMessage(w http.ResponseWriter, r *http.Request, id, user string) {
+	m := s.message(id)
+	if m == nil || m.Direction != "IN" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.URL.Query().Get("action") {
+	case "read":
+		now := s.Now()
+		s.setStatusAt(m, StatusRead, now)
+		m.ReadAt = now
+		m.ReadBy = user
+	case "unread":
+		s.setStatusAt(m, StatusUnread, s.Now())
+		m.ReadAt = time.Time{}
+		m.ReadBy = ""
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getMessageBatches(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var matched []*batch
+	for i := len(s.batches) - 1; i >= 0; i-- {
+		b := s.batches[i]
+		if q.Get("filterBy") == "account" && !strings.EqualFold(b.AccountReference, q.Get("filterValue")) {
+			continue
+		}
+
+		matched = append(matched, b)
+	}
+
+	startIndex, count := page(q)
+	v := messageBatchesXML{
+		StartIndex: startIndex,
+		TotalCount: len(matched),
+	}
+
+	if startIndex < len(matched) {
+		end := startIndex + count
+		if end > len(matched) {
+			end = len(matched)
+		}
+
+		for _, b := range matched[startIndex:end] {
+			v.Batches = append(v.Batches, s.messageBatchXML(b))
+		}
+	}
+	v.Count = len(v.Batches)
+
+	writeXML(w, "messagebatches", v)
+}
+
+func (s *Server) getMessageBatch(w http.ResponseWriter, r *http.Request, id string) {
+	b := s.batch(id)
+	if b == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeXML(w, "messagebatch", s.messageBatchXML(b))
+}
+
+func (s *Server) deleteMessageBatchSchedule(w http.ResponseWriter, r *http.Request, id string) {
+	b := s.batch(id)
+	if b == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	for _, m := range b.Messages {
+		if m.Status != StatusScheduled {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	for _, m := range b.Messages {
+		s.setStatus(m, StatusCancelled)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) messageURI(m *message) string {
+	return s.URL + "/v1.0/messageheaders/" + m.ID
+}
+
+func page(q url.Values) (startIndex, count int) {
+	count = defaultPageSize
+
+	if v, err := strconv.Atoi(q.Get("startindex")); err == nil && v >= 0 {
+		startIndex = v
+	}
+	if v, err := strconv.Atoi(q.Get("count")); err == nil && v > 0 {
+		count = v
+	}
+
+	return startIndex, count
+}
+
+func paginate(messages []*message, startIndex, count int) []*message {
+	if startIndex >= len(messages) {
+		return nil
+	}
+
+	end := startIndex + count
+	if end > len(messages) {
+		end = len(messages)
+	}
+
+	return messages[startIndex:end]
+}
+
+func between(q url.Values) (start, finish time.Time) {
+	start, _ = time.Parse(time.RFC3339, q.Get("start"))
+	finish, _ = time.Parse(time.RFC3339, q.Get("finish"))
+
+	return start, finish
+}
+
+func within(t, start, finish time.Time) bool {
+	if !start.IsZero() && t.Before(start) {
+		return false
+	}
+	if !finish.IsZero() && t.After(finish) {
+		return false
+	}
+
+	return true
+}
+
+// matches reports whether the message passes the filters in the query.
+func matches(q url.Values, m *message) bool {
+	filters := map[string]string{
+		"status":    m.Status,
+		"direction": m.Direction,
+		"to":        m.To,
+		"from":      m.From,
+		"batchid":   m.BatchID,
+		"type":      string(m.Type),
+	}
+
+	for key, value := range filters {
+		if want := q.Get(key); want != "" && !strings.EqualFold(want, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortMessages orders messages newest first, as the API does, unless the order
+// is "asc".
+func sortMessages(messages []*message, order string, at func(*message) time.Time) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		if order == "asc" {
+			return at(messages[i]).Before(at(messages[j]))
+		}
+
+		return at(messages[i]).After(at(messages[j]))
+	})
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
@@ -0,0 +1,419 @@
+package esendextest
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esendex/esendex-go-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleServer() {
+	server := NewServer()
+	defer server.Close()
+
+	server.AddAccount(Account{Reference: "EX0000000", Address: "447700900000"})
+
+	client := server.Client()
+
+	resp, err := client.Account("EX0000000").Send([]esendex.Message{
+		{To: "447700900001", Body: "Hello"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	server.SetBatchStatus(resp.BatchID, StatusDelivered)
+
+	message, _ := client.Message(resp.Messages[0].ID)
+	fmt.Println(message.Status)
+	// Output: Delivered
+}
+
+func newTestServer() *Server {
+	s := NewServer()
+	s.AddAccount(Account{
+		ID:                "accountid",
+		Reference:         "EX0000000",
+		Label:             "Test",
+		Address:           "447700900000",
+		Type:              "Professional",
+		MessagesRemaining: 100,
+		ExpiresOn:         time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		Role:              "PowerUser",
+	})
+	s.AddAccount(Account{
+		ID:                "otherid",
+		Reference:         "EX0000001",
+		Address:           "447700900999",
+		MessagesRemaining: 10,
+	})
+
+	return s
+}
+
+func TestNewServerAt(t *testing.T) {
+	s, err := NewServerAt("127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer s.Close()
+
+	assert.True(t, strings.HasPrefix(s.URL, "http://127.0.0.1:"))
+
+	s.AddAccount(Account{Reference: "EX0000000"})
+
+	accounts, err := s.Client().Accounts()
+	if assert.Nil(t, err) {
+		assert.Len(t, accounts.Accounts, 1)
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	client := s.Client()
+	assert := assert.New(t)
+
+	resp, err := client.Accounts()
+	if assert.Nil(err) && assert.Len(resp.Accounts, 2) {
+		account := resp.Accounts[0]
+
+		assert.Equal("accountid", account.ID)
+		assert.Equal("EX0000000", account.Reference)
+		assert.Equal("Test", account.Label)
+		assert.Equal("447700900000", account.Address)
+		assert.Equal("Professional", account.Type)
+		assert.Equal(100, account.MessagesRemaining)
+		assert.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), account.ExpiresOn)
+		assert.Equal("PowerUser", account.Role)
+	}
+
+	account, err := client.AccountByID("otherid")
+	if assert.Nil(err) {
+		assert.Equal("EX0000001", account.Reference)
+	}
+
+	_, err = client.AccountByID("missing")
+	assert.Equal(esendex.ClientError{Method: "GET", Path: "/v1.0/accounts/missing", Code: 404}, err)
+}
+
+func TestSendAndSent(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	client := s.Client()
+	account := client.Account("EX0000000")
+	assert := assert.New(t)
+
+	resp, err := account.Send([]esendex.Message{
+		{To: "447700900001", Body: "First"},
+		{To: "447700900002", Body: "Second", MessageType: esendex.Voice},
+	})
+	if !assert.Nil(err) || !assert.Len(resp.Messages, 2) {
+		return
+	}
+	assert.NotEmpty(resp.BatchID)
+
+	sent, err := account.Sent()
+	if assert.Nil(err) && assert.Len(sent.Messages, 2) {
+		assert.Equal(2, sent.TotalCount)
+
+		for _, message := range sent.Messages {
+			assert.Equal(StatusSubmitted, message.Status)
+			assert.Equal("447700900000", message.From)
+			assert.Equal("OUT", message.Direction)
+			assert.Equal(resp.BatchID, message.BatchID)
+			assert.Equal("user", message.Username)
+		}
+	}
+
+	other, err := client.Account("EX0000001").Sent()
+	if assert.Nil(err) {
+		assert.Empty(other.Messages)
+	}
+
+	message, err := client.Message(resp.Messages[1].ID)
+	if assert.Nil(err) {
+		assert.Equal("447700900002", message.To)
+		assert.Equal(esendex.Voice, message.Type)
+		if assert.NotNil(message.BatchID) {
+			assert.Equal(resp.BatchID, *message.BatchID)
+		}
+	}
+
+	body, err := client.Body(message)
+	if assert.Nil(err) {
+		assert.Equal("Second", body.Text)
+	}
+
+	accounts, err := client.Accounts()
+	if assert.Nil(err) {
+		assert.Equal(98, accounts.Accounts[0].MessagesRemaining)
+	}
+}
+
+func TestSentPaging(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	account := s.Client().Account("EX0000000")
+	assert := assert.New(t)
+
+	for i := 0; i < 5; i++ {
+		_, err := account.Send([]esendex.Message{{To: "447700900001", Body: "Hi"}})
+		assert.Nil(err)
+	}
+
+	sent, err := account.Sent(esendex.Page(3, 10))
+	if assert.Nil(err) {
+		assert.Equal(3, sent.StartIndex)
+		assert.Equal(2, sent.Count)
+		assert.Equal(5, sent.TotalCount)
+		assert.Len(sent.Messages, 2)
+	}
+}
+
+func TestBatches(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	client := s.Client()
+	account := client.Account("EX0000000")
+	assert := assert.New(t)
+
+	resp, err := account.Send([]esendex.Message{
+		{To: "447700900001", Body: "First"},
+		{To: "447700900002", Body: "Second"},
+	})
+	if !assert.Nil(err) {
+		return
+	}
+
+	batches, err := account.Batches()
+	if assert.Nil(err) && assert.Len(batches.Batches, 1) {
+		assert.Equal(resp.BatchID, batches.Batches[0].ID)
+		assert.Equal(2, batches.Batches[0].BatchSize)
+		assert.Equal(map[string]int{"submitted": 2}, batches.Batches[0].Status)
+	}
+
+	assert.Nil(s.Advance(resp.BatchID))
+	assert.Nil(s.SetMessageStatus(resp.Messages[0].ID, StatusFailed))
+
+	batch, err := client.Batch(resp.BatchID)
+	if assert.Nil(err) {
+		assert.Equal(map[string]int{"sent": 1, "failed": 1}, batch.Status)
+		assert.Equal("EX0000000", batch.AccountReference)
+	}
+
+	assert.Nil(s.Advance(resp.BatchID))
+
+	batch, err = client.Batch(resp.BatchID)
+	if assert.Nil(err) {
+		assert.Equal(map[string]int{"delivered": 1, "failed": 1}, batch.Status)
+	}
+
+	message, err := client.Message(resp.Messages[1].ID)
+	if assert.Nil(err) {
+		assert.False(message.SentAt.IsZero())
+		assert.False(message.DeliveredAt.IsZero())
+	}
+
+	other, err := client.Account("EX0000001").Batches()
+	if assert.Nil(err) {
+		assert.Empty(other.Batches)
+	}
+}
+
+func TestCancelBatch(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	client := s.Client()
+	account := client.Account("EX0000000")
+	assert := assert.New(t)
+
+	scheduled, err := account.SendAt(time.Now().Add(time.Hour), []esendex.Message{
+		{To: "447700900001", Body: "Later"},
+	})
+	if !assert.Nil(err) {
+		return
+	}
+
+	immediate, err := account.Send([]esendex.Message{
+		{To: "447700900001", Body: "Now"},
+	})
+	if !assert.Nil(err) {
+		return
+	}
+
+	assert.Nil(client.CancelBatch(scheduled.BatchID))
+	assert.NotNil(client.CancelBatch(immediate.BatchID))
+
+	batch, err := client.Batch(scheduled.BatchID)
+	if assert.Nil(err) {
+		assert.Equal(map[string]int{"cancelled": 1}, batch.Status)
+	}
+}
+
+func TestReceive(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	client := s.Client()
+	assert := assert.New(t)
+
+	id, err := s.Receive("EX0000000", "447700900123", "Hello there")
+	assert.Nil(err)
+
+	_, err = s.Receive("EX9999999", "447700900123", "Hello there")
+	assert.Equal(ErrNotFound, err)
+
+	received, err := client.Account("EX0000000").Received()
+	if assert.Nil(err) && assert.Len(received.Messages, 1) {
+		message := received.Messages[0]
+
+		assert.Equal(id, message.ID)
+		assert.Equal("447700900123", message.From)
+		assert.Equal("447700900000", message.To)
+		assert.Equal("IN", message.Direction)
+		assert.False(message.ReceivedAt.IsZero())
+		assert.True(message.ReadAt.IsZero())
+
+		body, err := client.Body(message)
+		if assert.Nil(err) {
+			assert.Equal("Hello there", body.Text)
+		}
+	}
+
+	all, err := client.Received()
+	if assert.Nil(err) {
+		assert.Len(all.Messages, 1)
+	}
+
+	other, err := client.Account("EX0000001").Received()
+	if assert.Nil(err) {
+		assert.Empty(other.Messages)
+	}
+
+	sent, err := client.Sent()
+	if assert.Nil(err) {
+		assert.Empty(sent.Messages)
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	client := s.Client()
+	assert := assert.New(t)
+
+	id, _ := s.Receive("EX0000000", "447700900123", "Hello there")
+
+	assert.Nil(client.MarkRead(id))
+
+	message, err := client.Message(id)
+	if assert.Nil(err) {
+		assert.Equal(StatusRead, message.Status)
+		assert.False(message.ReadAt.IsZero())
+		assert.Equal("user", message.ReadBy)
+	}
+
+	assert.Nil(client.MarkUnread(id))
+
+	message, err = client.Message(id)
+	if assert.Nil(err) {
+		assert.Equal(StatusUnread, message.Status)
+		assert.True(message.ReadAt.IsZero())
+	}
+
+	assert.Equal(esendex.ClientError{Method: "PUT", Path: "/v1.0/inbox/messages/unknown", Code: 404}, client.MarkRead("unknown"))
+}
+
+func TestCredentials(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	s.Username = "admin"
+	s.Password = "secret"
+
+	assert := assert.New(t)
+
+	_, err := s.Client().Accounts()
+	assert.Nil(err)
+
+	client := esendex.New("admin", "wrong")
+	client.BaseURL = s.Client().BaseURL
+
+	_, err = client.Accounts()
+	assert.Equal(esendex.ClientError{Method: "GET", Path: "/v1.0/accounts", Code: 401}, err)
+}
+
+func TestSession(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	s.Username = "admin"
+	s.Password = "secret"
+
+	assert := assert.New(t)
+
+	id, err := s.Client().CreateSession()
+	assert.Nil(err)
+
+	client := esendex.NewSession(id)
+	client.BaseURL = s.Client().BaseURL
+
+	_, err = client.Accounts()
+	assert.Nil(err)
+
+	client = esendex.NewSession("unknown")
+	client.BaseURL = s.Client().BaseURL
+
+	_, err = client.Accounts()
+	assert.Equal(esendex.ClientError{Method: "GET", Path: "/v1.0/accounts", Code: 401}, err)
+}
+
+func TestSentFilters(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	account := s.Client().Account("EX0000000")
+	assert := assert.New(t)
+
+	first, err := account.Send([]esendex.Message{{To: "447700900001", Body: "Hi"}})
+	assert.Nil(err)
+	second, err := account.Send([]esendex.Message{
+		{To: "447700900001", Body: "Hi"},
+		{To: "447700900002", Body: "Hi"},
+	})
+	assert.Nil(err)
+
+	s.SetBatchStatus(first.BatchID, StatusDelivered)
+
+	sent, err := account.Sent(esendex.To("447700900001"), esendex.Sort(esendex.Ascending))
+	if assert.Nil(err) && assert.Len(sent.Messages, 2) {
+		assert.Equal(first.Messages[0].ID, sent.Messages[0].ID)
+		assert.Equal(second.Messages[0].ID, sent.Messages[1].ID)
+	}
+
+	sent, err = account.Sent(esendex.Batch(second.BatchID))
+	if assert.Nil(err) {
+		assert.Equal(2, sent.TotalCount)
+	}
+
+	sent, err = account.Sent(esendex.Status(StatusDelivered))
+	if assert.Nil(err) && assert.Len(sent.Messages, 1) {
+		assert.Equal(first.Messages[0].ID, sent.Messages[0].ID)
+	}
+
+	sent, err = account.Sent(esendex.Direction(esendex.Inbound))
+	if assert.Nil(err) {
+		assert.Equal(0, sent.TotalCount)
+	}
+}
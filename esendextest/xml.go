@@ -0,0 +1,277 @@
+package esendextest
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const namespace = "http://api.esendex.com/ns/"
+
+const (
+	accountsTimeFormat      = "2006-01-02T15:04:05"
+	messageHeaderTimeFormat = "2006-01-02T15:04:05.999999999Z"
+)
+
+// batchStatuses lists the status elements of a message batch in the order the
+// API returns them.
+var batchStatuses = []string{
+	"acknowledged",
+	"authorisationfailed",
+	"connecting",
+	"delivered",
+	"failed",
+	"partiallydelivered",
+	"rejected",
+	"scheduled",
+	"sent",
+	"submitted",
+	"validityperiodexpired",
+	"cancelled",
+}
+
+func writeXML(w http.ResponseWriter, name string, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+
+	xml.NewEncoder(w).EncodeElement(v, xml.StartElement{
+		Name: xml.Name{Space: namespace, Local: name},
+	})
+}
+
+func decodeXML(r *http.Request, v interface{}) error {
+	defer r.Body.Close()
+
+	return xml.NewDecoder(r.Body).Decode(v)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(messageHeaderTimeFormat)
+}
+
+type linkXML struct {
+	ID  string `xml:"id,attr,omitempty"`
+	URI string `xml:"uri,attr"`
+}
+
+type sessionXML struct {
+	ID string `xml:"id"`
+}
+
+type accountsXML struct {
+	Accounts []accountXML `xml:"account"`
+}
+
+type accountXML struct {
+	ID                string  `xml:"id,attr"`
+	URI               string  `xml:"uri,attr"`
+	Reference         string  `xml:"reference"`
+	Label             string  `xml:"label"`
+	Address           string  `xml:"address"`
+	Type              string  `xml:"type"`
+	MessagesRemaining int     `xml:"messagesremaining"`
+	ExpiresOn         string  `xml:"expireson,omitempty"`
+	Role              string  `xml:"role"`
+	Settings          linkXML `xml:"settings"`
+}
+
+func (s *Server) accountXML(account *Account) accountXML {
+	uri := s.URL + "/v1.0/accounts/" + account.ID
+
+	v := accountXML{
+		ID:                account.ID,
+		URI:               uri,
+		Reference:         account.Reference,
+		Label:             account.Label,
+		Address:           account.Address,
+		Type:              account.Type,
+		MessagesRemaining: account.MessagesRemaining,
+		Role:              account.Role,
+		Settings:          linkXML{URI: uri + "/settings"},
+	}
+
+	if !account.ExpiresOn.IsZero() {
+		v.ExpiresOn = account.ExpiresOn.UTC().Format(accountsTimeFormat)
+	}
+
+	return v
+}
+
+type dispatchRequestXML struct {
+	AccountReference string                      `xml:"accountreference"`
+	SendAt           *time.Time                  `xml:"sendat"`
+	From             string                      `xml:"from"`
+	Messages         []dispatchRequestMessageXML `xml:"message"`
+}
+
+type dispatchRequestMessageXML struct {
+	To           string `xml:"to"`
+	From         string `xml:"from"`
+	Type         string `xml:"type"`
+	Lang         string `xml:"lang"`
+	Validity     int    `xml:"validity"`
+	CharacterSet string `xml:"characterset"`
+	Retries      int    `xml:"retries"`
+	Body         string `xml:"body"`
+}
+
+type dispatchResponseXML struct {
+	BatchID  string    `xml:"batchid,attr"`
+	Messages []linkXML `xml:"messageheader"`
+}
+
+type messageHeadersXML struct {
+	StartIndex int                `xml:"startindex,attr"`
+	Count      int                `xml:"count,attr"`
+	TotalCount int                `xml:"totalcount,attr"`
+	Messages   []messageHeaderXML `xml:"messageheader"`
+}
+
+type messageHeaderXML struct {
+	ID            string            `xml:"id,attr"`
+	URI           string            `xml:"uri,attr"`
+	Reference     string            `xml:"reference"`
+	Status        string            `xml:"status"`
+	LastStatusAt  string            `xml:"laststatusat,omitempty"`
+	SubmittedAt   string            `xml:"submittedat,omitempty"`
+	ReceivedAt    string            `xml:"receivedat,omitempty"`
+	Type          string            `xml:"type"`
+	To            string            `xml:"to>phonenumber"`
+	From          string            `xml:"from>phonenumber"`
+	Summary       string            `xml:"summary"`
+	Body          linkXML           `xml:"body"`
+	Direction     string            `xml:"direction"`
+	ReadAt        string            `xml:"readat,omitempty"`
+	SentAt        string            `xml:"sentat,omitempty"`
+	DeliveredAt   string            `xml:"deliveredat,omitempty"`
+	ReadBy        string            `xml:"readby,omitempty"`
+	Parts         int               `xml:"parts"`
+	Username      string            `xml:"username,omitempty"`
+	FailureReason *failureReasonXML `xml:"failurereason"`
+	Batch         *batchLinkXML     `xml:"batch"`
+}
+
+type failureReasonXML struct {
+	Code        int    `xml:"code"`
+	Description string `xml:"description"`
+	Permanent   bool   `xml:"permanentfailure"`
+}
+
+type batchLinkXML struct {
+	ID   string `xml:"id,attr"`
+	Link string `xml:"link,attr"`
+}
+
+func (s *Server) messageHeaderXML(m *message) messageHeaderXML {
+	uri := s.messageURI(m)
+
+	summary := m.Body
+	if runes := []rune(summary); len(runes) > 50 {
+		summary = string(runes[:50])
+	}
+
+	v := messageHeaderXML{
+		ID:           m.ID,
+		URI:          uri,
+		Reference:    m.AccountReference,
+		Status:       m.Status,
+		LastStatusAt: formatTime(m.LastStatusAt),
+		SubmittedAt:  formatTime(m.SubmittedAt),
+		ReceivedAt:   formatTime(m.ReceivedAt),
+		Type:         string(m.Type),
+		To:           m.To,
+		From:         m.From,
+		Summary:      summary,
+		Body:         linkXML{URI: uri + "/body"},
+		Direction:    m.Direction,
+		ReadAt:       formatTime(m.ReadAt),
+		SentAt:       formatTime(m.SentAt),
+		DeliveredAt:  formatTime(m.DeliveredAt),
+		ReadBy:       m.ReadBy,
+		Parts:        m.Parts,
+		Username:     m.Username,
+	}
+
+	if m.FailureReason != nil {
+		v.FailureReason = &failureReasonXML{
+			Code:        m.FailureReason.Code,
+			Description: m.FailureReason.Description,
+			Permanent:   m.FailureReason.Permanent,
+		}
+	}
+
+	if m.BatchID != "" {
+		v.Batch = &batchLinkXML{
+			ID:   m.BatchID,
+			Link: s.URL + "/v1.1/messagebatches/" + m.BatchID,
+		}
+	}
+
+	return v
+}
+
+type messageBodyXML struct {
+	BodyText     string `xml:"bodytext"`
+	CharacterSet string `xml:"characterset"`
+}
+
+type messageBatchesXML struct {
+	StartIndex int               `xml:"startindex,attr"`
+	Count      int               `xml:"count,attr"`
+	TotalCount int               `xml:"totalcount,attr"`
+	Batches    []messageBatchXML `xml:"messagebatch"`
+}
+
+type messageBatchXML struct {
+	ID                 string         `xml:"id,attr"`
+	URI                string         `xml:"uri,attr"`
+	CreatedAt          time.Time      `xml:"createdat"`
+	BatchSize          int            `xml:"batchsize"`
+	PersistedBatchSize int            `xml:"persistedbatchsize"`
+	Status             batchStatusXML `xml:"status"`
+	AccountReference   string         `xml:"accountreference"`
+	CreatedBy          string         `xml:"createdby"`
+	Name               string         `xml:"name"`
+}
+
+// batchStatusXML counts the messages in a batch by lower-cased status.
+type batchStatusXML map[string]int
+
+func (v batchStatusXML) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, status := range batchStatuses {
+		if err := e.EncodeElement(v[status], xml.StartElement{Name: xml.Name{Local: status}}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+func (s *Server) messageBatchXML(b *batch) messageBatchXML {
+	status := batchStatusXML{}
+	for _, m := range b.Messages {
+		status[strings.ToLower(m.Status)]++
+	}
+
+	return messageBatchXML{
+		ID:                 b.ID,
+		URI:                s.URL + "/v1.1/messagebatches/" + b.ID,
+		CreatedAt:          b.CreatedAt.UTC(),
+		BatchSize:          len(b.Messages),
+		PersistedBatchSize: len(b.Messages),
+		Status:             status,
+		AccountReference:   b.AccountReference,
+		CreatedBy:          b.CreatedBy,
+		Name:               b.Name,
+	}
+}
@@ -0,0 +1,248 @@
+package esendex
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// Group is a contact group to create or update.
+type Group struct {
+	Name string
+}
+
+// GroupsResponse is a list of returned contact groups along with the paging
+// information.
+type GroupsResponse struct {
+	Paging
+	Groups []GroupResponse
+}
+
+// GroupResponse is a single contact group.
+type GroupResponse struct {
+	ID               string
+	URI              string
+	AccountReference string
+	Name             string
+	ContactCount     int
+	ContactsURI      string
+}
+
+// Groups returns a list of contact groups for the account.
+func (c *AccountClient) Groups(opts ...Option) (*GroupsResponse, error) {
+	accountOption := func(p *Params) error {
+		return p.Set("accountreference", c.reference)
+	}
+
+	req, err := c.newRequest("GET", "/v1.0/groups", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyOptions(req, append(opts, accountOption)); err != nil {
+		return nil, err
+	}
+
+	var v groupsResponse
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &GroupsResponse{
+		Paging: Paging{
+			StartIndex: v.StartIndex,
+			Count:      v.Count,
+			TotalCount: v.TotalCount,
+		},
+		Groups: make([]GroupResponse, len(v.Groups)),
+	}
+
+	for i, group := range v.Groups {
+		response.Groups[i] = GroupResponse{
+			ID:               group.ID,
+			URI:              group.URI,
+			AccountReference: group.AccountReference,
+			Name:             group.Name,
+			ContactCount:     group.ContactCount,
+			ContactsURI:      group.Contacts.URI,
+		}
+	}
+
+	return response, nil
+}
+
+// Group returns the contact group with the given id.
+func (c *AccountClient) Group(id string) (*GroupResponse, error) {
+	req, err := c.newRequest("GET", "/v1.0/groups/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doGroup(req)
+}
+
+// CreateGroup adds a contact group to the account.
+func (c *AccountClient) CreateGroup(group Group) (*GroupResponse, error) {
+	body := groupRequest{
+		AccountReference: c.reference,
+		Name:             group.Name,
+	}
+
+	req, err := c.newRequest("POST", "/v1.0/groups", &body)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doGroup(req)
+}
+
+// UpdateGroup replaces the details of the contact group with the given id.
+func (c *AccountClient) UpdateGroup(id string, group Group) (*GroupResponse, error) {
+	body := groupRequest{
+		AccountReference: c.reference,
+		Name:             group.Name,
+	}
+
+	req, err := c.newRequest("PUT", "/v1.0/groups/"+id, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doGroup(req)
+}
+
+// DeleteGroup removes the contact group with the given id. The contacts in the
+// group are not deleted.
+func (c *AccountClient) DeleteGroup(id string) error {
+	req, err := c.newRequest("DELETE", "/v1.0/groups/"+id, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(req, nil)
+	return err
+}
+
+// GroupContacts returns a list of the contacts that are members of the group
+// with the given id.
+func (c *AccountClient) GroupContacts(id string, opts ...Option) (*ContactsResponse, error) {
+	req, err := c.newRequest("GET", "/v1.0/groups/"+id+"/contacts", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyOptions(req, opts); err != nil {
+		return nil, err
+	}
+
+	var v contactsResponse
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &ContactsResponse{
+		Paging: Paging{
+			StartIndex: v.StartIndex,
+			Count:      v.Count,
+			TotalCount: v.TotalCount,
+		},
+		Contacts: make([]ContactResponse, len(v.Contacts)),
+	}
+
+	for i, contact := range v.Contacts {
+		response.Contacts[i] = ContactResponse{
+			ID:               contact.ID,
+			URI:              contact.URI,
+			AccountReference: contact.AccountReference,
+			FirstName:        contact.FirstName,
+			LastName:         contact.LastName,
+			QuickName:        contact.QuickName,
+			MobileNumber:     contact.MobileNumber,
+			GroupsURI:        contact.Groups.URI,
+		}
+	}
+
+	return response, nil
+}
+
+// AddGroupContacts adds the contacts with the given ids to the group.
+func (c *AccountClient) AddGroupContacts(id string, contactIDs []string) error {
+	body := groupContactsRequest{
+		Contacts: make([]groupContactsRequestContact, len(contactIDs)),
+	}
+
+	for i, contactID := range contactIDs {
+		body.Contacts[i] = groupContactsRequestContact{ID: contactID}
+	}
+
+	req, err := c.newRequest("POST", "/v1.0/groups/"+id+"/contacts", &body)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(req, nil)
+	return err
+}
+
+// RemoveGroupContact removes the contact with the given id from the group. The
+// contact itself is not deleted.
+func (c *AccountClient) RemoveGroupContact(id, contactID string) error {
+	req, err := c.newRequest("DELETE", "/v1.0/groups/"+id+"/contacts/"+contactID, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(req, nil)
+	return err
+}
+
+func (c *AccountClient) doGroup(req *http.Request) (*GroupResponse, error) {
+	var v groupsResponseGroup
+	if _, err := c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &GroupResponse{
+		ID:               v.ID,
+		URI:              v.URI,
+		AccountReference: v.AccountReference,
+		Name:             v.Name,
+		ContactCount:     v.ContactCount,
+		ContactsURI:      v.Contacts.URI,
+	}
+
+	return response, nil
+}
+
+type groupRequest struct {
+	XMLName          xml.Name `xml:"group"`
+	AccountReference string   `xml:"accountreference"`
+	Name             string   `xml:"name"`
+}
+
+type groupContactsRequest struct {
+	XMLName  xml.Name                      `xml:"contacts"`
+	Contacts []groupContactsRequestContact `xml:"contact"`
+}
+
+type groupContactsRequestContact struct {
+	ID string `xml:"id,attr"`
+}
+
+type groupsResponse struct {
+	XMLName    xml.Name              `xml:"http://api.esendex.com/ns/ groups"`
+	StartIndex int                   `xml:"startindex,attr"`
+	Count      int                   `xml:"count,attr"`
+	TotalCount int                   `xml:"totalcount,attr"`
+	Groups     []groupsResponseGroup `xml:"group"`
+}
+
+type groupsResponseGroup struct {
+	ID               string `xml:"id,attr"`
+	URI              string `xml:"uri,attr"`
+	AccountReference string `xml:"accountreference"`
+	Name             string `xml:"name"`
+	ContactCount     int    `xml:"contactcount"`
+	Contacts         struct {
+		URI string `xml:"uri,attr"`
+	} `xml:"contacts"`
+}
@@ -0,0 +1,244 @@
+package esendex
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroups(t *testing.T) {
+	const (
+		startIndex       = 0
+		count            = 15
+		totalCount       = 30
+		id               = "groupid"
+		uri              = "http://somegroup"
+		accountReference = "EX0000000"
+		name             = "Customers"
+		contactCount     = 12
+		contactsURI      = "http://somegroup/contacts"
+	)
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<groups startindex="`+strconv.Itoa(startIndex)+`" count="`+strconv.Itoa(count)+`" totalcount="`+strconv.Itoa(totalCount)+`" xmlns="http://api.esendex.com/ns/">
+ <group id="`+id+`" uri="`+uri+`">
+  <name>`+name+`</name>
+  <accountreference>`+accountReference+`</accountreference>
+  <contactcount>`+strconv.Itoa(contactCount)+`</contactcount>
+  <contacts uri="`+contactsURI+`" />
+ </group>
+</groups>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	result, err := client.Account(accountReference).Groups()
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/groups", h.Request.URL.Path)
+
+	if user, pass, ok := h.Request.BasicAuth(); assert.True(ok) {
+		assert.Equal("user", user)
+		assert.Equal("pass", pass)
+	}
+
+	query := h.Request.URL.Query()
+	assert.Equal(accountReference, query.Get("accountreference"))
+
+	assert.Equal(startIndex, result.StartIndex)
+	assert.Equal(count, result.Count)
+	assert.Equal(totalCount, result.TotalCount)
+
+	if assert.Equal(1, len(result.Groups)) {
+		group := result.Groups[0]
+
+		assert.Equal(id, group.ID)
+		assert.Equal(uri, group.URI)
+		assert.Equal(accountReference, group.AccountReference)
+		assert.Equal(name, group.Name)
+		assert.Equal(contactCount, group.ContactCount)
+		assert.Equal(contactsURI, group.ContactsURI)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<group id="groupid" uri="http://somegroup" xmlns="http://api.esendex.com/ns/">
+ <name>Customers</name>
+ <accountreference>EX0000000</accountreference>
+ <contactcount>3</contactcount>
+</group>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	group, err := client.Account("EX0000000").Group("groupid")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/groups/groupid", h.Request.URL.String())
+
+	assert.Equal("groupid", group.ID)
+	assert.Equal("Customers", group.Name)
+	assert.Equal(3, group.ContactCount)
+}
+
+func TestCreateGroup(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<group id="groupid" uri="http://somegroup" xmlns="http://api.esendex.com/ns/">
+ <name>Customers</name>
+ <accountreference>EX0000000</accountreference>
+</group>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	group, err := client.Account("EX0000000").CreateGroup(Group{Name: "Customers"})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("POST", h.Request.Method)
+	assert.Equal("/v1.0/groups", h.Request.URL.String())
+	assert.Equal("<group>"+
+		"<accountreference>EX0000000</accountreference>"+
+		"<name>Customers</name>"+
+		"</group>", h.RequestBody)
+
+	assert.Equal("groupid", group.ID)
+}
+
+func TestUpdateGroup(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<group id="groupid" uri="http://somegroup" xmlns="http://api.esendex.com/ns/">
+ <name>Old customers</name>
+</group>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	group, err := client.Account("EX0000000").UpdateGroup("groupid", Group{Name: "Old customers"})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("PUT", h.Request.Method)
+	assert.Equal("/v1.0/groups/groupid", h.Request.URL.String())
+	assert.Equal("<group>"+
+		"<accountreference>EX0000000</accountreference>"+
+		"<name>Old customers</name>"+
+		"</group>", h.RequestBody)
+
+	assert.Equal("Old customers", group.Name)
+}
+
+func TestDeleteGroup(t *testing.T) {
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	err := client.Account("EX0000000").DeleteGroup("groupid")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("DELETE", h.Request.Method)
+	assert.Equal("/v1.0/groups/groupid", h.Request.URL.String())
+}
+
+func TestGroupContacts(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<contacts startindex="5" count="1" totalcount="6" xmlns="http://api.esendex.com/ns/">
+ <contact id="contactid" uri="http://somecontact">
+  <mobilenumber>447700900000</mobilenumber>
+ </contact>
+</contacts>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	result, err := client.Account("EX0000000").GroupContacts("groupid", Page(5, 10))
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/groups/groupid/contacts", h.Request.URL.Path)
+
+	query := h.Request.URL.Query()
+	assert.Equal("5", query.Get("startindex"))
+	assert.Equal("10", query.Get("count"))
+
+	assert.Equal(6, result.TotalCount)
+	if assert.Equal(1, len(result.Contacts)) {
+		assert.Equal("contactid", result.Contacts[0].ID)
+		assert.Equal("447700900000", result.Contacts[0].MobileNumber)
+	}
+}
+
+func TestAddGroupContacts(t *testing.T) {
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	err := client.Account("EX0000000").AddGroupContacts("groupid", []string{"first", "second"})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("POST", h.Request.Method)
+	assert.Equal("/v1.0/groups/groupid/contacts", h.Request.URL.String())
+	assert.Equal("<contacts>"+
+		`<contact id="first"></contact>`+
+		`<contact id="second"></contact>`+
+		"</contacts>", h.RequestBody)
+}
+
+func TestRemoveGroupContact(t *testing.T) {
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	err := client.Account("EX0000000").RemoveGroupContact("groupid", "contactid")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("DELETE", h.Request.Method)
+	assert.Equal("/v1.0/groups/groupid/contacts/contactid", h.Request.URL.String())
+}
@@ -0,0 +1,82 @@
+package esendex
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Metrics receives measurements of the requests made by a Client. It can be
+// implemented to feed Prometheus, OpenTelemetry or any other metrics library.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// RequestCompleted is called after each request. The endpoint is the path
+	// with any ids replaced by {id}, such as /v1.0/messageheaders/{id}, so that
+	// it can be used as a label. The status is 0 if no response was received.
+	RequestCompleted(method, endpoint string, status int, duration time.Duration)
+
+	// MessagesDispatched is called after messages are successfully sent, with
+	// the number of messages and the number of SMS segments they will use. A
+	// message sent to a group is counted once.
+	MessagesDispatched(accountReference string, messages, segments int)
+}
+
+// Tracer starts spans around the calls a Client makes to the API. It follows
+// the shape of OpenTelemetry's trace.Tracer, so can be implemented by a thin
+// adapter around one. Implementations must be safe for concurrent use.
+//
+// Only SendContext, SendFromContext and SendAtContext take a context, so only
+// their send and dispatch spans are children of any span in it. The spans for
+// every other call, including Send, SendFrom, SendAt and the opt-out requests
+// an OptOutFilter makes while refreshing, are roots.
+type Tracer interface {
+	// Start starts a span, which is a child of any span in ctx, and returns a
+	// context containing it.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single operation started by a Tracer.
+type Span interface {
+	// SetAttribute records a value, which is a string, int or bool, on the
+	// span. Attribute keys follow the OpenTelemetry semantic conventions where
+	// one applies, otherwise they are prefixed with "esendex.".
+	SetAttribute(key string, value interface{})
+
+	// RecordError records that the operation failed.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+}
+
+// staticPathSegment matches the parts of API paths that are not ids.
+var staticPathSegment = regexp.MustCompile(`^(?:[a-z]+|v\d+\.\d+)$`)
+
+// endpointName replaces the ids in the path with {id}.
+func endpointName(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if part != "" && !staticPathSegment.MatchString(part) {
+			parts[i] = "{id}"
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// startSpan starts a span using the Tracer, if set. The returned span is never
+// nil.
+func (c *Client) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if c.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return c.Tracer.Start(ctx, name)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) RecordError(err error)                      {}
+func (noopSpan) End()                                       {}
@@ -0,0 +1,235 @@
+package esendex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingMetrics struct {
+	mu         sync.Mutex
+	requests   []string
+	dispatched []string
+}
+
+func (m *recordingMetrics) RequestCompleted(method, endpoint string, status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = append(m.requests, fmt.Sprintf("%s %s %d", method, endpoint, status))
+}
+
+func (m *recordingMetrics) MessagesDispatched(accountReference string, messages, segments int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dispatched = append(m.dispatched, fmt.Sprintf("%s %d %d", accountReference, messages, segments))
+}
+
+type spanKey struct{}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span := &recordingSpan{Name: name, Attributes: map[string]interface{}{}}
+	if parent, ok := ctx.Value(spanKey{}).(*recordingSpan); ok {
+		span.Parent = parent.Name
+	}
+
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+type recordingSpan struct {
+	Name       string
+	Parent     string
+	Attributes map[string]interface{}
+	Errors     []error
+	Ended      bool
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) { s.Attributes[key] = value }
+func (s *recordingSpan) RecordError(err error)                      { s.Errors = append(s.Errors, err) }
+func (s *recordingSpan) End()                                       { s.Ended = true }
+
+func TestMetrics(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders batchid="batchid" xmlns="http://api.esendex.com/ns/">
+ <messageheader uri="http://somemessage" id="messageid" />
+ <messageheader uri="http://somemessage" id="messageid2" />
+</messageheaders>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	metrics := &recordingMetrics{}
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.Metrics = metrics
+
+	_, err := client.Account("EX00000").Send([]Message{
+		{To: "447700900123", Body: "Hello"},
+		{To: "447700900124", Body: strings.Repeat("a", 200)},
+	})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal([]string{"POST /v1.0/messagedispatcher 200"}, metrics.requests)
+	assert.Equal([]string{"EX00000 2 3"}, metrics.dispatched)
+}
+
+func TestMetricsWhenNotOkResponse(t *testing.T) {
+	h := newRecordingHandler("", 404, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	metrics := &recordingMetrics{}
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.Metrics = metrics
+
+	client.Message("0e7d9a4c-1f2b-4c3d-8e5f-6a7b8c9d0e1f")
+	client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Hello"}})
+
+	assert.Equal(t, []string{
+		"GET /v1.0/messageheaders/{id} 404",
+		"POST /v1.0/messagedispatcher 404",
+	}, metrics.requests)
+	assert.Empty(t, metrics.dispatched)
+}
+
+func TestTracer(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders batchid="batchid" xmlns="http://api.esendex.com/ns/">
+ <messageheader uri="http://somemessage" id="messageid" />
+</messageheaders>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	tracer := &recordingTracer{}
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.Tracer = tracer
+
+	_, err := client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Hello"}})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	if assert.Len(tracer.spans, 2) {
+		send, request := tracer.spans[0], tracer.spans[1]
+
+		assert.Equal("esendex.Send", send.Name)
+		assert.True(send.Ended)
+		assert.Equal("EX00000", send.Attributes["esendex.account_reference"])
+		assert.Equal(1, send.Attributes["esendex.message_count"])
+		assert.Equal("batchid", send.Attributes["esendex.batch_id"])
+
+		assert.Equal("POST /v1.0/messagedispatcher", request.Name)
+		assert.Equal("esendex.Send", request.Parent)
+		assert.True(request.Ended)
+		assert.Equal("POST", request.Attributes["http.request.method"])
+		assert.Equal("/v1.0/messagedispatcher", request.Attributes["url.path"])
+		assert.Equal(200, request.Attributes["http.response.status_code"])
+		assert.Empty(request.Errors)
+	}
+}
+
+func TestTracerSendContext(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders batchid="batchid" xmlns="http://api.esendex.com/ns/">
+ <messageheader uri="http://somemessage" id="messageid" />
+</messageheaders>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	messages := []Message{{To: "447700900123", Body: "Hello"}}
+
+	testCases := map[string]func(ctx context.Context, account *AccountClient) (*SendResponse, error){
+		"SendContext": func(ctx context.Context, account *AccountClient) (*SendResponse, error) {
+			return account.SendContext(ctx, messages)
+		},
+		"SendFromContext": func(ctx context.Context, account *AccountClient) (*SendResponse, error) {
+			return account.SendFromContext(ctx, "Company", messages)
+		},
+		"SendAtContext": func(ctx context.Context, account *AccountClient) (*SendResponse, error) {
+			return account.SendAtContext(ctx, time.Now().Add(time.Hour), messages)
+		},
+	}
+
+	for name, send := range testCases {
+		tracer := &recordingTracer{}
+
+		client := New("user", "pass")
+		client.BaseURL, _ = url.Parse(s.URL)
+		client.Tracer = tracer
+
+		ctx, parent := tracer.Start(context.Background(), "handler")
+		_, err := send(ctx, client.Account("EX00000"))
+		parent.End()
+
+		assert.Nil(t, err, name)
+
+		if assert.Len(t, tracer.spans, 3, name) {
+			assert.Equal(t, "", tracer.spans[0].Parent, name)
+			assert.Equal(t, "handler", tracer.spans[1].Parent, name)
+			assert.Equal(t, "esendex.Send", tracer.spans[1].Name, name)
+			assert.Equal(t, "esendex.Send", tracer.spans[2].Parent, name)
+		}
+	}
+}
+
+func TestTracerWhenNotOkResponse(t *testing.T) {
+	h := newRecordingHandler("", 500, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	tracer := &recordingTracer{}
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.Tracer = tracer
+
+	client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Hello"}})
+
+	expected := ClientError{Method: "POST", Path: "/v1.0/messagedispatcher", Code: 500}
+
+	if assert.Len(t, tracer.spans, 2) {
+		for _, span := range tracer.spans {
+			if assert.Len(t, span.Errors, 1) {
+				assert.True(t, errors.Is(span.Errors[0], expected))
+			}
+		}
+	}
+}
+
+func TestEndpointName(t *testing.T) {
+	testCases := map[string]string{
+		"/v1.0/accounts": "/v1.0/accounts",
+		"/v1.0/messageheaders/4b7f5d0e-2c1a-4e4f-9a3b-6d1c2e8f0a11/body":     "/v1.0/messageheaders/{id}/body",
+		"/v1.0/inbox/EX0000000/messages":                                     "/v1.0/inbox/{id}/messages",
+		"/v1.1/messagebatches/4b7f5d0e-2c1a-4e4f-9a3b-6d1c2e8f0a11/schedule": "/v1.1/messagebatches/{id}/schedule",
+	}
+
+	for path, expected := range testCases {
+		assert.Equal(t, expected, endpointName(path), path)
+	}
+}
@@ -0,0 +1,108 @@
+package esendex
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// messageTextElement matches the XML elements that carry the text of messages
+// in requests to, and responses from, the API.
+var messageTextElement = regexp.MustCompile(`(<(?:body|bodytext|summary|messagetext)>)([^<]*)(</)`)
+
+// LogOptions controls what is logged about each request when Client.Logger is
+// set.
+//
+// Every request is logged with its method, path, status and duration: at
+// slog.LevelInfo if successful, slog.LevelWarn if the API returned an error
+// status, and slog.LevelError if no response was received. Credentials are
+// never logged, including the id returned by CreateSession.
+//
+// The client does not retry requests, so records have no retry count. A
+// Middleware that retries by calling next again gives one record per attempt.
+type LogOptions struct {
+	// Bodies adds a second record at slog.LevelDebug with the request and
+	// response bodies.
+	Bodies bool
+
+	// KeepPhoneNumbers and KeepMessageText stop phone numbers and the text of
+	// messages being redacted from logged bodies.
+	KeepPhoneNumbers bool
+	KeepMessageText  bool
+}
+
+// logRequest logs the outcome of a request. If the response body is to be
+// logged it is read and replaced, so that it can still be decoded.
+func (c *Client) logRequest(req *http.Request, resp *http.Response, duration time.Duration, err error) {
+	if c.Logger == nil {
+		return
+	}
+
+	ctx := req.Context()
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case err != nil:
+		level = slog.LevelError
+		attrs = append(attrs, slog.Any("error", err))
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	default:
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+
+	attrs = append(attrs, slog.Duration("duration", duration))
+
+	c.Logger.LogAttrs(ctx, level, "esendex request", attrs...)
+
+	if !c.LogOptions.Bodies || !c.Logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs = []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+	}
+
+	if req.GetBody != nil {
+		if body, berr := req.GetBody(); berr == nil {
+			data, _ := ioutil.ReadAll(body)
+			body.Close()
+
+			if len(data) > 0 {
+				attrs = append(attrs, slog.String("request_body", c.redactLogBody(string(data))))
+			}
+		}
+	}
+
+	if resp != nil && resp.Body != nil {
+		data, rerr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+		if rerr == nil && len(data) > 0 {
+			attrs = append(attrs, slog.String("response_body", c.redactLogBody(string(data))))
+		}
+	}
+
+	c.Logger.LogAttrs(ctx, slog.LevelDebug, "esendex request body", attrs...)
+}
+
+func (c *Client) redactLogBody(body string) string {
+	body = redactSession(redactBody(body, c.LogOptions.KeepPhoneNumbers))
+
+	if !c.LogOptions.KeepMessageText {
+		body = messageTextElement.ReplaceAllString(body, "${1}"+redacted+"${3}")
+	}
+
+	return body
+}
@@ -0,0 +1,188 @@
+package esendex
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const logSendResponse = `<?xml version="1.0" encoding="utf-8"?>
+<messageheaders batchid="batchid" xmlns="http://api.esendex.com/ns/">
+ <messageheader uri="http://somemessage" id="messageid" />
+</messageheaders>`
+
+func newLoggingClient(baseURL string, level slog.Level) (*Client, *bytes.Buffer) {
+	var buf bytes.Buffer
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(baseURL)
+	client.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: level}))
+
+	return client, &buf
+}
+
+func logRecords(buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var record map[string]interface{}
+		json.Unmarshal([]byte(line), &record)
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	h := newRecordingHandler(logSendResponse, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client, buf := newLoggingClient(s.URL, slog.LevelInfo)
+
+	_, err := client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Secret"}})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	records := logRecords(buf)
+	if assert.Len(records, 1) {
+		assert.Equal("INFO", records[0]["level"])
+		assert.Equal("esendex request", records[0]["msg"])
+		assert.Equal("POST", records[0]["method"])
+		assert.Equal("/v1.0/messagedispatcher", records[0]["path"])
+		assert.Equal(float64(200), records[0]["status"])
+		assert.Contains(records[0], "duration")
+	}
+
+	assert.NotContains(buf.String(), "pass")
+	assert.NotContains(buf.String(), "Secret")
+}
+
+func TestLoggerWhenNotOkResponse(t *testing.T) {
+	h := newRecordingHandler("", 403, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client, buf := newLoggingClient(s.URL, slog.LevelInfo)
+
+	client.Accounts()
+
+	records := logRecords(buf)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "WARN", records[0]["level"])
+		assert.Equal(t, float64(403), records[0]["status"])
+	}
+}
+
+func TestLoggerWhenRequestFails(t *testing.T) {
+	client, buf := newLoggingClient("http://127.0.0.1:0", slog.LevelInfo)
+
+	client.Accounts()
+
+	records := logRecords(buf)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "ERROR", records[0]["level"])
+		assert.Contains(t, records[0], "error")
+	}
+}
+
+func TestLoggerBodies(t *testing.T) {
+	h := newRecordingHandler(logSendResponse, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client, buf := newLoggingClient(s.URL, slog.LevelDebug)
+	client.LogOptions.Bodies = true
+
+	resp, err := client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Secret"}})
+
+	assert := assert.New(t)
+
+	if assert.Nil(err) {
+		assert.Equal("batchid", resp.BatchID)
+	}
+
+	records := logRecords(buf)
+	if assert.Len(records, 2) {
+		assert.Equal("DEBUG", records[1]["level"])
+		assert.Equal("esendex request body", records[1]["msg"])
+
+		requestBody, _ := records[1]["request_body"].(string)
+		assert.Contains(requestBody, "<to>XXXXXXXXX123</to>")
+		assert.Contains(requestBody, "<body>REDACTED</body>")
+
+		responseBody, _ := records[1]["response_body"].(string)
+		assert.Contains(responseBody, `batchid="batchid"`)
+	}
+
+	assert.NotContains(buf.String(), "pass")
+	assert.NotContains(buf.String(), "Secret")
+	assert.NotContains(buf.String(), "447700900123")
+}
+
+func TestLoggerBodiesUnredacted(t *testing.T) {
+	h := newRecordingHandler(logSendResponse, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client, buf := newLoggingClient(s.URL, slog.LevelDebug)
+	client.LogOptions = LogOptions{Bodies: true, KeepPhoneNumbers: true, KeepMessageText: true}
+
+	client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Secret"}})
+
+	assert.Contains(t, buf.String(), "<to>447700900123</to>")
+	assert.Contains(t, buf.String(), "<body>Secret</body>")
+}
+
+func TestLoggerBodiesRedactsSession(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<session xmlns="http://api.esendex.com/ns/">
+ <id>B3C6A7E5-2FE8-4D3C-A5D7-5E8D7C2B0A11</id>
+</session>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client, buf := newLoggingClient(s.URL, slog.LevelDebug)
+	client.LogOptions = LogOptions{Bodies: true, KeepPhoneNumbers: true, KeepMessageText: true}
+
+	id, err := client.CreateSession()
+
+	assert := assert.New(t)
+
+	if assert.Nil(err) {
+		assert.Equal("B3C6A7E5-2FE8-4D3C-A5D7-5E8D7C2B0A11", id)
+	}
+
+	records := logRecords(buf)
+	if assert.Len(records, 2) {
+		responseBody, _ := records[1]["response_body"].(string)
+		assert.Contains(responseBody, "<id>REDACTED</id>")
+	}
+
+	assert.NotContains(buf.String(), "B3C6A7E5-2FE8-4D3C-A5D7-5E8D7C2B0A11")
+}
+
+func TestLoggerBodiesNeedDebugLevel(t *testing.T) {
+	h := newRecordingHandler(logSendResponse, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client, buf := newLoggingClient(s.URL, slog.LevelInfo)
+	client.LogOptions.Bodies = true
+
+	client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Secret"}})
+
+	assert.Len(t, logRecords(buf), 1)
+}
@@ -20,7 +20,9 @@ type FailureReason struct {
 	Permanent   bool
 }
 
-type messageWithBody interface {
+// MessageWithBody is implemented by the message types returned from the API
+// that have a body which can be fetched with Body.
+type MessageWithBody interface {
 	getBodyURI() string
 }
 
@@ -31,7 +33,7 @@ type SentMessagesResponse struct {
 	Messages []SentMessageResponse
 }
 
-// SentMessageResponse is a single sent message. It implements messageWithBody.
+// SentMessageResponse is a single sent message. It implements MessageWithBody.
 type SentMessageResponse struct {
 	ID            string
 	URI           string
@@ -54,7 +56,7 @@ type SentMessageResponse struct {
 
 func (r SentMessageResponse) getBodyURI() string { return r.bodyURI }
 
-// MessageResponse is a single message. It implements messageWithBody.
+// MessageResponse is a single message. It implements MessageWithBody.
 //
 // BatchID might be nil, as inbound messages do not have a batch ID.
 type MessageResponse struct {
@@ -91,7 +93,7 @@ type ReceivedMessagesResponse struct {
 	Messages []ReceivedMessageResponse
 }
 
-// ReceivedMessageResponse is a single received message. It implements messageWithBody.
+// ReceivedMessageResponse is a single received message. It implements MessageWithBody.
 type ReceivedMessageResponse struct {
 	ID         string
 	URI        string
@@ -125,8 +127,8 @@ func (c *Client) Sent(opts ...Option) (*SentMessagesResponse, error) {
 		return nil, err
 	}
 
-	for _, opt := range opts {
-		opt(req)
+	if err := applyOptions(req, opts); err != nil {
+		return nil, err
 	}
 
 	var v messageHeadersResponse
@@ -150,8 +152,8 @@ func (c *Client) Sent(opts ...Option) (*SentMessagesResponse, error) {
 			URI:          message.URI,
 			Reference:    message.Reference,
 			Status:       message.Status,
-			LastStatusAt: message.LastStatusAt.Time,
-			SubmittedAt:  message.SubmittedAt.Time,
+			LastStatusAt: c.in(message.LastStatusAt.Time),
+			SubmittedAt:  c.in(message.SubmittedAt.Time),
 			Type:         MessageType(message.Type),
 			To:           message.To,
 			From:         message.From,
@@ -182,8 +184,8 @@ func (c *Client) Received(opts ...Option) (*ReceivedMessagesResponse, error) {
 		return nil, err
 	}
 
-	for _, opt := range opts {
-		opt(req)
+	if err := applyOptions(req, opts); err != nil {
+		return nil, err
 	}
 
 	var v inboxResponse
@@ -206,7 +208,7 @@ func (c *Client) Received(opts ...Option) (*ReceivedMessagesResponse, error) {
 			URI:        message.URI,
 			Reference:  message.Reference,
 			Status:     message.Status,
-			ReceivedAt: message.ReceivedAt.Time,
+			ReceivedAt: c.in(message.ReceivedAt.Time),
 			Type:       MessageType(message.Type),
 			To:         message.To,
 			From:       message.From,
@@ -214,7 +216,7 @@ func (c *Client) Received(opts ...Option) (*ReceivedMessagesResponse, error) {
 			bodyURI:    message.Body.URI,
 			Direction:  message.Direction,
 			Parts:      message.Parts,
-			ReadAt:     message.ReadAt.Time,
+			ReadAt:     c.in(message.ReadAt.Time),
 			ReadBy:     message.ReadBy,
 		}
 	}
@@ -239,18 +241,18 @@ func (c *Client) Message(id string) (*MessageResponse, error) {
 		URI:          v.URI,
 		Reference:    v.Reference,
 		Status:       v.Status,
-		LastStatusAt: v.LastStatusAt.Time,
-		SubmittedAt:  v.SubmittedAt.Time,
-		ReceivedAt:   v.ReceivedAt.Time,
+		LastStatusAt: c.in(v.LastStatusAt.Time),
+		SubmittedAt:  c.in(v.SubmittedAt.Time),
+		ReceivedAt:   c.in(v.ReceivedAt.Time),
 		Type:         MessageType(v.Type),
 		To:           v.To,
 		From:         v.From,
 		Summary:      v.Summary,
 		bodyURI:      v.Body.URI,
 		Direction:    v.Direction,
-		ReadAt:       v.ReadAt.Time,
-		SentAt:       v.SentAt.Time,
-		DeliveredAt:  v.DeliveredAt.Time,
+		ReadAt:       c.in(v.ReadAt.Time),
+		SentAt:       c.in(v.SentAt.Time),
+		DeliveredAt:  c.in(v.DeliveredAt.Time),
 		ReadBy:       v.ReadBy,
 		Parts:        v.Parts,
 		Username:     v.Username,
@@ -273,7 +275,7 @@ func (c *Client) Message(id string) (*MessageResponse, error) {
 }
 
 // Body returns the full body of a single message.
-func (c *Client) Body(message messageWithBody) (*MessageBody, error) {
+func (c *Client) Body(message MessageWithBody) (*MessageBody, error) {
 	u, err := url.Parse(message.getBodyURI())
 	if err != nil {
 		return nil, err
@@ -295,6 +297,26 @@ func (c *Client) Body(message messageWithBody) (*MessageBody, error) {
 	}, nil
 }
 
+// MarkRead marks the received message with the given id as read.
+func (c *Client) MarkRead(id string) error {
+	return c.markInboxMessage(id, "read")
+}
+
+// MarkUnread marks the received message with the given id as unread.
+func (c *Client) MarkUnread(id string) error {
+	return c.markInboxMessage(id, "unread")
+}
+
+func (c *Client) markInboxMessage(id, action string) error {
+	req, err := c.newRequest("PUT", "/v1.0/inbox/messages/"+id+"?action="+action, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(req, nil)
+	return err
+}
+
 type messageBodyResponse struct {
 	XMLName      xml.Name `xml:"http://api.esendex.com/ns/ messagebody"`
 	BodyText     string   `xml:"bodytext"`
@@ -373,24 +395,20 @@ type inboxResponseMessageHeader struct {
 	ReadBy    string            `xml:"readby"`
 }
 
-const messageHeaderTimeFormat = "2006-01-02T15:04:05.999999999"
-const messageHeaderTimeFormatZ = "2006-01-02T15:04:05.999999999Z"
+const messageHeaderTimeFormat = "2006-01-02T15:04:05.999999999Z"
 
 type messageHeaderTime struct {
 	time.Time
 }
 
 func (t messageHeaderTime) MarshalText() ([]byte, error) {
-	return []byte(t.Format(messageHeaderTimeFormat)), nil
+	return []byte(formatTime(t.Time, messageHeaderTimeFormat)), nil
 }
 
 func (t *messageHeaderTime) UnmarshalText(data []byte) error {
-	g, err := time.ParseInLocation(messageHeaderTimeFormat, string(data), time.UTC)
+	g, err := parseTime(string(data))
 	if err != nil {
-		g, err = time.ParseInLocation(messageHeaderTimeFormatZ, string(data), time.UTC)
-		if err != nil {
-			return err
-		}
+		return err
 	}
 	*t = messageHeaderTime{g}
 	return nil
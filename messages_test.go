@@ -1,6 +1,7 @@
 package esendex
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http/httptest"
@@ -891,3 +892,145 @@ func TestMessageBody(t *testing.T) {
 	assert.Equal(bodyText, body.Text)
 	assert.Equal(characterSet, body.CharacterSet)
 }
+
+func TestMarkRead(t *testing.T) {
+	const messageID = "messageid"
+
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	err := client.MarkRead(messageID)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("PUT", h.Request.Method)
+	assert.Equal("/v1.0/inbox/messages/"+messageID+"?action=read", h.Request.URL.String())
+
+	if user, pass, ok := h.Request.BasicAuth(); assert.True(ok) {
+		assert.Equal("user", user)
+		assert.Equal("pass", pass)
+	}
+}
+
+func TestMarkUnread(t *testing.T) {
+	const messageID = "messageid"
+
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	err := client.MarkUnread(messageID)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("PUT", h.Request.Method)
+	assert.Equal("/v1.0/inbox/messages/"+messageID+"?action=unread", h.Request.URL.String())
+}
+
+func TestMarkReadWhenNotOkResponse(t *testing.T) {
+	h := newRecordingHandler("", 404, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	err := client.MarkRead("messageid")
+
+	assert.Equal(t, ClientError{Method: "PUT", Path: "/v1.0/inbox/messages/messageid", Code: 404}, err)
+}
+
+func TestSentWithFilters(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders startindex="0" count="0" totalcount="0" xmlns="http://api.esendex.com/ns/" />`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	_, err := client.Sent(
+		Status("Delivered"),
+		Direction(Outbound),
+		To("447700900123"),
+		From("Esendex"),
+		Batch("batchid"),
+		Type(SMS),
+		Sort(Ascending),
+	)
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	query := h.Request.URL.Query()
+	assert.Equal("Delivered", query.Get("status"))
+	assert.Equal("OUT", query.Get("direction"))
+	assert.Equal("447700900123", query.Get("to"))
+	assert.Equal("Esendex", query.Get("from"))
+	assert.Equal("batchid", query.Get("batchid"))
+	assert.Equal("SMS", query.Get("type"))
+	assert.Equal("asc", query.Get("sortorder"))
+}
+
+func TestSentWithInvalidOptions(t *testing.T) {
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	now := time.Now()
+
+	testCases := map[string][]Option{
+		"option status is given more than once":         {Status("Delivered"), Status("Delivered")},
+		"option startindex is given more than once":     {Page(0, 10), Page(10, 10)},
+		"option startindex is negative":                 {Page(-1, 10)},
+		"option count is less than 1":                   {Page(0, 0)},
+		"option finish is before start":                 {Between(now, now.Add(-time.Hour))},
+		"option to is empty":                            {To("")},
+		`option direction has unknown value "Sideways"`: {Direction("Sideways")},
+		`option sortorder has unknown value "random"`:   {Sort("random")},
+	}
+
+	for expected, opts := range testCases {
+		_, err := client.Sent(opts...)
+
+		var oerr OptionError
+		if assert.True(t, errors.As(err, &oerr), expected) {
+			assert.Equal(t, "esendex: "+expected, err.Error())
+		}
+	}
+
+	assert.Empty(t, h.Request.Method)
+}
+
+func TestAccountSentWithCustomOption(t *testing.T) {
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	custom := func(p *Params) error {
+		return p.Set("accountReference", "EX0000001")
+	}
+
+	_, err := client.Account("EX0000000").Sent(custom)
+
+	assert.Equal(t, OptionError{Param: "accountReference", Reason: "is given more than once"}, err)
+	assert.Empty(t, h.Request.Method)
+}
@@ -0,0 +1,101 @@
+package esendex
+
+import (
+	"encoding/xml"
+	"net/http"
+	"reflect"
+)
+
+// Call is a single request made by a Client, as seen by its Middleware.
+type Call struct {
+	// Request is the request to be made. Middleware can modify it, for example
+	// to add headers, or replace it.
+	Request *http.Request
+
+	// Messages are the messages being dispatched, when the call is made by
+	// Send, SendFrom or SendAt. Changing them has no effect, as the request has
+	// already been built, but returning an error stops them being sent.
+	Messages []Message
+
+	// Result is the value the response body was decoded into. It is set once
+	// the next Handler returns without error, and is nil for calls that do not
+	// expect a body. Its type is internal to this package, but it can be logged
+	// with fmt or encoding/json.
+	Result interface{}
+
+	target interface{}
+
+	// decoded is the last response that was checked and decoded, so that outer
+	// Handlers pass it through rather than decoding it again.
+	decoded *http.Response
+}
+
+// Handler makes a call, returning the response.
+type Handler func(call *Call) (*http.Response, error)
+
+// Middleware wraps each call a Client makes. It can inspect or modify the
+// request before calling next, return an error or its own response without
+// calling next, and inspect the response and Result after next returns.
+//
+// A response returned without calling next is handled as if it came from the
+// API: a status outside of the 200 range gives a ClientError, otherwise the
+// body is decoded. Responses from the API are not logged or measured when a
+// middleware short-circuits the call.
+type Middleware func(call *Call, next Handler) (*http.Response, error)
+
+// handler returns the Handler for a call, with each of the Client's Middleware
+// wrapped around sending the request to the API. The first Middleware is the
+// outermost.
+func (c *Client) handler() Handler {
+	h := decoding(c.roundTrip)
+
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		m, next := c.Middleware[i], h
+		h = decoding(func(call *Call) (*http.Response, error) {
+			return m(call, next)
+		})
+	}
+
+	return h
+}
+
+// decoding wraps h so that the response is checked and decoded into the call's
+// Result before it is returned, if that has not already happened. A middleware
+// may call next more than once, so each response is checked, not each call.
+func decoding(h Handler) Handler {
+	return func(call *Call) (*http.Response, error) {
+		resp, err := h(call)
+		if err != nil || resp == nil || resp == call.decoded {
+			return resp, err
+		}
+
+		call.decoded = resp
+		call.Result = nil
+		if resp.Body == nil {
+			resp.Body = http.NoBody
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return resp, ClientError{
+				Method: call.Request.Method,
+				Path:   call.Request.URL.Path,
+				Code:   resp.StatusCode,
+			}
+		}
+
+		if call.target != nil {
+			// Clear anything decoded from an earlier response to the same call.
+			target := reflect.ValueOf(call.target).Elem()
+			target.Set(reflect.Zero(target.Type()))
+
+			if err := xml.NewDecoder(resp.Body).Decode(call.target); err != nil {
+				return resp, err
+			}
+
+			call.Result = call.target
+		}
+
+		return resp, nil
+	}
+}
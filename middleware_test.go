@@ -0,0 +1,259 @@
+package esendex
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleMiddleware() {
+	client := New("user@example.com", "pass")
+
+	// Stop messages being sent to anything but test numbers.
+	client.Middleware = append(client.Middleware, func(call *Call, next Handler) (*http.Response, error) {
+		for _, message := range call.Messages {
+			if !strings.HasPrefix(message.To, "4477009") {
+				return nil, errors.New("blocked send to " + message.To)
+			}
+		}
+
+		return next(call)
+	})
+}
+
+func TestMiddlewareModifiesRequest(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<accounts xmlns="http://api.esendex.com/ns/" />`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	var order []string
+	client.Middleware = []Middleware{
+		func(call *Call, next Handler) (*http.Response, error) {
+			order = append(order, "first")
+			call.Request.Header.Set("X-Request-Id", "abc")
+			return next(call)
+		},
+		func(call *Call, next Handler) (*http.Response, error) {
+			order = append(order, "second")
+			return next(call)
+		},
+	}
+
+	_, err := client.Accounts()
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal([]string{"first", "second"}, order)
+	assert.Equal("abc", h.Request.Header.Get("X-Request-Id"))
+}
+
+func TestMiddlewareShortCircuits(t *testing.T) {
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse("http://127.0.0.1:0")
+
+	var called bool
+	client.Middleware = []Middleware{
+		func(call *Call, next Handler) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Body: ioutil.NopCloser(strings.NewReader(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders batchid="fakebatch" xmlns="http://api.esendex.com/ns/">
+ <messageheader uri="http://somemessage" id="fakemessage" />
+</messageheaders>`)),
+			}, nil
+		},
+		func(call *Call, next Handler) (*http.Response, error) {
+			called = true
+			return next(call)
+		},
+	}
+
+	resp, err := client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Hello"}})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.False(called)
+	assert.Equal("fakebatch", resp.BatchID)
+	assert.Equal([]SendResponseMessage{{URI: "http://somemessage", ID: "fakemessage"}}, resp.Messages)
+}
+
+func TestMiddlewareShortCircuitsWithErrorStatus(t *testing.T) {
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse("http://127.0.0.1:0")
+
+	client.Middleware = []Middleware{
+		func(call *Call, next Handler) (*http.Response, error) {
+			return &http.Response{StatusCode: 403}, nil
+		},
+	}
+
+	_, err := client.Accounts()
+
+	assert.Equal(t, ClientError{Method: "GET", Path: "/v1.0/accounts", Code: 403}, err)
+}
+
+func TestMiddlewareBlocksSend(t *testing.T) {
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	blocked := errors.New("blocked")
+	client.Middleware = []Middleware{
+		func(call *Call, next Handler) (*http.Response, error) {
+			for _, message := range call.Messages {
+				if strings.HasPrefix(message.To, "44") {
+					return nil, blocked
+				}
+			}
+
+			return next(call)
+		},
+	}
+
+	_, err := client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Hello"}})
+
+	assert.Equal(t, blocked, err)
+	assert.Equal(t, "", h.Request.Method)
+}
+
+func TestMiddlewareObservesResult(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders batchid="batchid" xmlns="http://api.esendex.com/ns/">
+ <messageheader uri="http://somemessage" id="messageid" />
+</messageheaders>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	var audit []string
+	client.Middleware = []Middleware{
+		func(call *Call, next Handler) (*http.Response, error) {
+			resp, err := next(call)
+			if err == nil {
+				data, _ := json.Marshal(call.Result)
+				audit = append(audit, string(data))
+			}
+
+			return resp, err
+		},
+	}
+
+	_, err := client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Hello"}})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	if assert.Len(audit, 1) {
+		assert.Contains(audit[0], `"BatchID":"batchid"`)
+		assert.Contains(audit[0], `"ID":"messageid"`)
+	}
+}
+
+func TestMiddlewareSeesClientError(t *testing.T) {
+	h := newRecordingHandler("", 500, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	var seen error
+	client.Middleware = []Middleware{
+		func(call *Call, next Handler) (*http.Response, error) {
+			resp, err := next(call)
+			seen = err
+			return resp, err
+		},
+	}
+
+	_, err := client.Accounts()
+
+	expected := ClientError{Method: "GET", Path: "/v1.0/accounts", Code: 500}
+	assert.Equal(t, expected, err)
+	assert.Equal(t, expected, seen)
+}
+
+// sequenceHandler responds to each request with the next of its responses.
+type sequenceHandler struct {
+	codes  []int
+	bodies []string
+	served int
+}
+
+func (h *sequenceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	i := h.served
+	h.served++
+
+	w.WriteHeader(h.codes[i])
+	w.Write([]byte(h.bodies[i]))
+}
+
+func retrying(call *Call, next Handler) (*http.Response, error) {
+	if resp, err := next(call); err == nil {
+		return resp, err
+	}
+
+	return next(call)
+}
+
+func TestMiddlewareCallsNextTwice(t *testing.T) {
+	h := &sequenceHandler{codes: []int{500, 401}, bodies: []string{"", ""}}
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.Middleware = []Middleware{retrying}
+
+	_, err := client.Accounts()
+
+	assert.Equal(t, 2, h.served)
+	assert.Equal(t, ClientError{Method: "GET", Path: "/v1.0/accounts", Code: 401}, err)
+}
+
+func TestMiddlewareCallsNextTwiceAndSucceeds(t *testing.T) {
+	accounts := `<?xml version="1.0" encoding="utf-8"?>
+<accounts xmlns="http://api.esendex.com/ns/">
+ <account id="accountid"><reference>EX0000000</reference></account>
+</accounts>`
+
+	h := &sequenceHandler{codes: []int{200, 200}, bodies: []string{accounts, accounts}}
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.Middleware = []Middleware{
+		func(call *Call, next Handler) (*http.Response, error) {
+			next(call)
+			return next(call)
+		},
+	}
+
+	resp, err := client.Accounts()
+
+	assert := assert.New(t)
+
+	assert.Equal(2, h.served)
+	if assert.Nil(err) && assert.Len(resp.Accounts, 1) {
+		assert.Equal("EX0000000", resp.Accounts[0].Reference)
+	}
+}
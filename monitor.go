@@ -0,0 +1,135 @@
+package esendex
+
+import (
+	"context"
+	"time"
+)
+
+const defaultMonitorInterval = 5 * time.Minute
+
+// CreditAlert is passed to a CreditMonitor's Alert function when an account is
+// running low on messages or is close to expiring.
+type CreditAlert struct {
+	Account AccountResponse
+
+	// LowCredit is true when the account has fewer messages remaining than the
+	// monitor's Threshold.
+	LowCredit bool
+
+	// Expiring is true when the account expires within the monitor's
+	// ExpiryWarning.
+	Expiring bool
+}
+
+// CreditMonitor polls the accounts available to a Client and reports those that
+// are running low on credit or are due to expire.
+//
+// An alert is only raised when an account first crosses a limit; it is raised
+// again if the account recovers and then crosses the limit once more.
+type CreditMonitor struct {
+	Client *Client
+
+	// Interval is the time to wait between polls, it defaults to 5 minutes.
+	Interval time.Duration
+
+	// Threshold raises an alert when MessagesRemaining drops below it. A zero
+	// value disables credit alerts.
+	Threshold int
+
+	// ExpiryWarning raises an alert when ExpiresOn is within this duration. A
+	// zero value disables expiry alerts.
+	ExpiryWarning time.Duration
+
+	// References restricts the monitor to the listed accounts. If empty all
+	// accounts available to the user are monitored.
+	References []string
+
+	// Alert is called for each account that crosses a limit.
+	Alert func(CreditAlert)
+
+	// Error, if set, is called with any error encountered while polling by Run.
+	Error func(error)
+
+	now    func() time.Time
+	alerts map[string]CreditAlert
+}
+
+// Check polls the accounts once, calling Alert for any that have crossed a
+// limit since the previous check.
+func (m *CreditMonitor) Check() error {
+	resp, err := m.Client.Accounts()
+	if err != nil {
+		return err
+	}
+
+	if m.alerts == nil {
+		m.alerts = map[string]CreditAlert{}
+	}
+
+	now := time.Now
+	if m.now != nil {
+		now = m.now
+	}
+
+	for _, account := range resp.Accounts {
+		if !m.monitors(account.Reference) {
+			continue
+		}
+
+		alert := CreditAlert{
+			Account:   account,
+			LowCredit: m.Threshold > 0 && account.MessagesRemaining < m.Threshold,
+			Expiring: m.ExpiryWarning > 0 && !account.ExpiresOn.IsZero() &&
+				account.ExpiresOn.Sub(now()) < m.ExpiryWarning,
+		}
+
+		previous := m.alerts[account.Reference]
+		m.alerts[account.Reference] = alert
+
+		if (alert.LowCredit && !previous.LowCredit) || (alert.Expiring && !previous.Expiring) {
+			if m.Alert != nil {
+				m.Alert(alert)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Run calls Check every Interval until the context is cancelled. It is intended
+// to be run in its own goroutine.
+func (m *CreditMonitor) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = defaultMonitorInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Check(); err != nil && m.Error != nil {
+			m.Error(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *CreditMonitor) monitors(reference string) bool {
+	if len(m.References) == 0 {
+		return true
+	}
+
+	for _, r := range m.References {
+		if r == reference {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,123 @@
+package esendex
+
+import (
+	"context"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleCreditMonitor() {
+	client := New("user@example.com", "pass")
+
+	monitor := &CreditMonitor{
+		Client:        client,
+		Interval:      time.Hour,
+		Threshold:     100,
+		ExpiryWarning: 7 * 24 * time.Hour,
+		Alert: func(alert CreditAlert) {
+			log.Printf("%s: %d messages remaining, expires %v",
+				alert.Account.Reference, alert.Account.MessagesRemaining, alert.Account.ExpiresOn)
+		},
+	}
+
+	go monitor.Run(context.Background())
+}
+
+func creditMonitorBody(messagesRemaining int, expiresOn string) string {
+	return `<?xml version="1.0" encoding="utf-8"?>
+<accounts xmlns="http://api.esendex.com/ns/">
+ <account id="first" uri="http://first">
+  <reference>EX000001</reference>
+  <messagesremaining>` + strconv.Itoa(messagesRemaining) + `</messagesremaining>
+  <expireson>` + expiresOn + `</expireson>
+ </account>
+ <account id="second" uri="http://second">
+  <reference>EX000002</reference>
+  <messagesremaining>0</messagesremaining>
+  <expireson>2012-01-01T00:00:00</expireson>
+ </account>
+</accounts>`
+}
+
+func TestCreditMonitorCheck(t *testing.T) {
+	h := newRecordingHandler(creditMonitorBody(500, "2013-01-01T00:00:00"), 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	var alerts []CreditAlert
+
+	monitor := &CreditMonitor{
+		Client:        client,
+		Threshold:     100,
+		ExpiryWarning: 24 * time.Hour,
+		References:    []string{"EX000001"},
+		Alert:         func(alert CreditAlert) { alerts = append(alerts, alert) },
+		now:           func() time.Time { return time.Date(2012, 6, 1, 0, 0, 0, 0, time.UTC) },
+	}
+
+	assert := assert.New(t)
+
+	assert.Nil(monitor.Check())
+	assert.Empty(alerts)
+
+	h.body = creditMonitorBody(50, "2013-01-01T00:00:00")
+	assert.Nil(monitor.Check())
+	if assert.Len(alerts, 1) {
+		assert.Equal("EX000001", alerts[0].Account.Reference)
+		assert.Equal(50, alerts[0].Account.MessagesRemaining)
+		assert.True(alerts[0].LowCredit)
+		assert.False(alerts[0].Expiring)
+	}
+
+	h.body = creditMonitorBody(40, "2013-01-01T00:00:00")
+	assert.Nil(monitor.Check())
+	assert.Len(alerts, 1)
+
+	h.body = creditMonitorBody(40, "2012-06-01T12:00:00")
+	assert.Nil(monitor.Check())
+	if assert.Len(alerts, 2) {
+		assert.True(alerts[1].LowCredit)
+		assert.True(alerts[1].Expiring)
+	}
+
+	h.body = creditMonitorBody(500, "2013-01-01T00:00:00")
+	assert.Nil(monitor.Check())
+	h.body = creditMonitorBody(50, "2013-01-01T00:00:00")
+	assert.Nil(monitor.Check())
+	assert.Len(alerts, 3)
+}
+
+func TestCreditMonitorRun(t *testing.T) {
+	h := newRecordingHandler("", 500, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errs := make(chan error, 1)
+	monitor := &CreditMonitor{
+		Client:   client,
+		Interval: time.Hour,
+		Error: func(err error) {
+			errs <- err
+			cancel()
+		},
+	}
+
+	assert := assert.New(t)
+
+	assert.Equal(context.Canceled, monitor.Run(ctx))
+	assert.Equal(ClientError{Method: "GET", Path: "/v1.0/accounts", Code: 500}, <-errs)
+}
@@ -0,0 +1,172 @@
+package esendex
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// InboundMessageNotification is pushed by the API when a message is received by
+// an account.
+type InboundMessageNotification struct {
+	ID          string
+	MessageID   string
+	AccountID   string
+	MessageText string
+	From        string
+	To          string
+}
+
+// MessageDeliveredNotification is pushed by the API when a sent message is
+// delivered.
+type MessageDeliveredNotification struct {
+	ID         string
+	MessageID  string
+	AccountID  string
+	OccurredAt time.Time
+}
+
+// MessageFailedNotification is pushed by the API when a sent message fails.
+type MessageFailedNotification struct {
+	ID            string
+	MessageID     string
+	AccountID     string
+	OccurredAt    time.Time
+	FailureReason *FailureReason
+}
+
+// NotificationHandler is an http.Handler that receives push notifications from
+// the API and passes them to the matching function. Notifications for which no
+// function is set are accepted and ignored.
+type NotificationHandler struct {
+	Inbound   func(InboundMessageNotification)
+	Delivered func(MessageDeliveredNotification)
+	Failed    func(MessageFailedNotification)
+
+	// Location, if set, is the location that OccurredAt is given in. Otherwise
+	// it is in UTC.
+	Location *time.Location
+}
+
+func (h NotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(body, &root); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch root.XMLName.Local {
+	case "InboundMessage":
+		var v inboundMessageNotification
+		if err := xml.Unmarshal(body, &v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if h.Inbound != nil {
+			h.Inbound(InboundMessageNotification{
+				ID:          v.ID,
+				MessageID:   v.MessageID,
+				AccountID:   v.AccountID,
+				MessageText: v.MessageText,
+				From:        v.From,
+				To:          v.To,
+			})
+		}
+
+	case "MessageDelivered":
+		var v messageDeliveredNotification
+		if err := xml.Unmarshal(body, &v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if h.Delivered != nil {
+			h.Delivered(MessageDeliveredNotification{
+				ID:         v.ID,
+				MessageID:  v.MessageID,
+				AccountID:  v.AccountID,
+				OccurredAt: inLocation(v.OccurredAt.Time, h.Location),
+			})
+		}
+
+	case "MessageFailed":
+		var v messageFailedNotification
+		if err := xml.Unmarshal(body, &v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if h.Failed != nil {
+			notification := MessageFailedNotification{
+				ID:         v.ID,
+				MessageID:  v.MessageID,
+				AccountID:  v.AccountID,
+				OccurredAt: inLocation(v.OccurredAt.Time, h.Location),
+			}
+
+			if v.FailureReason != nil {
+				notification.FailureReason = &FailureReason{
+					Code:        v.FailureReason.Code,
+					Description: v.FailureReason.Description,
+					Permanent:   v.FailureReason.Permanent,
+				}
+			}
+
+			h.Failed(notification)
+		}
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+type inboundMessageNotification struct {
+	XMLName     xml.Name `xml:"InboundMessage"`
+	ID          string   `xml:"Id"`
+	MessageID   string   `xml:"MessageId"`
+	AccountID   string   `xml:"AccountId"`
+	MessageText string   `xml:"MessageText"`
+	From        string   `xml:"From"`
+	To          string   `xml:"To"`
+}
+
+type messageDeliveredNotification struct {
+	XMLName    xml.Name          `xml:"MessageDelivered"`
+	ID         string            `xml:"Id"`
+	MessageID  string            `xml:"MessageId"`
+	AccountID  string            `xml:"AccountId"`
+	OccurredAt messageHeaderTime `xml:"OccurredAt"`
+}
+
+type messageFailedNotification struct {
+	XMLName       xml.Name                          `xml:"MessageFailed"`
+	ID            string                            `xml:"Id"`
+	MessageID     string                            `xml:"MessageId"`
+	AccountID     string                            `xml:"AccountId"`
+	OccurredAt    messageHeaderTime                 `xml:"OccurredAt"`
+	FailureReason *messageFailedNotificationFailure `xml:"FailureReason"`
+}
+
+type messageFailedNotificationFailure struct {
+	Code        int    `xml:"Code"`
+	Description string `xml:"Description"`
+	Permanent   bool   `xml:"PermanentFailure"`
+}
@@ -0,0 +1,130 @@
+package esendex
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleNotificationHandler() {
+	http.Handle("/esendex", NotificationHandler{
+		Inbound: func(n InboundMessageNotification) {
+			log.Printf("received from %s: %s", n.From, n.MessageText)
+		},
+		Failed: func(n MessageFailedNotification) {
+			log.Printf("message %s failed", n.MessageID)
+		},
+	})
+}
+
+func postNotification(h http.Handler, body string) int {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+
+	return w.Code
+}
+
+func TestNotificationHandlerInbound(t *testing.T) {
+	var got []InboundMessageNotification
+
+	h := NotificationHandler{
+		Inbound: func(n InboundMessageNotification) { got = append(got, n) },
+	}
+
+	code := postNotification(h, `<?xml version="1.0" encoding="utf-8"?>
+<InboundMessage>
+ <Id>notificationid</Id>
+ <MessageId>messageid</MessageId>
+ <AccountId>accountid</AccountId>
+ <MessageText>Hello</MessageText>
+ <From>447700900123</From>
+ <To>447700900000</To>
+</InboundMessage>`)
+
+	assert := assert.New(t)
+
+	assert.Equal(200, code)
+	assert.Equal([]InboundMessageNotification{{
+		ID:          "notificationid",
+		MessageID:   "messageid",
+		AccountID:   "accountid",
+		MessageText: "Hello",
+		From:        "447700900123",
+		To:          "447700900000",
+	}}, got)
+}
+
+func TestNotificationHandlerDelivered(t *testing.T) {
+	var got []MessageDeliveredNotification
+
+	h := NotificationHandler{
+		Delivered: func(n MessageDeliveredNotification) { got = append(got, n) },
+	}
+
+	code := postNotification(h, `<?xml version="1.0" encoding="utf-8"?>
+<MessageDelivered>
+ <Id>notificationid</Id>
+ <MessageId>messageid</MessageId>
+ <AccountId>accountid</AccountId>
+ <OccurredAt>2012-01-01T12:00:05.12</OccurredAt>
+</MessageDelivered>`)
+
+	assert := assert.New(t)
+
+	assert.Equal(200, code)
+	assert.Equal([]MessageDeliveredNotification{{
+		ID:         "notificationid",
+		MessageID:  "messageid",
+		AccountID:  "accountid",
+		OccurredAt: time.Date(2012, 1, 1, 12, 0, 5, 120000000, time.UTC),
+	}}, got)
+}
+
+func TestNotificationHandlerFailed(t *testing.T) {
+	var got []MessageFailedNotification
+
+	h := NotificationHandler{
+		Failed: func(n MessageFailedNotification) { got = append(got, n) },
+	}
+
+	code := postNotification(h, `<?xml version="1.0" encoding="utf-8"?>
+<MessageFailed>
+ <Id>notificationid</Id>
+ <MessageId>messageid</MessageId>
+ <AccountId>accountid</AccountId>
+ <OccurredAt>2012-01-01T12:00:05Z</OccurredAt>
+ <FailureReason>
+  <Code>14</Code>
+  <Description>Unknown subscriber</Description>
+  <PermanentFailure>true</PermanentFailure>
+ </FailureReason>
+</MessageFailed>`)
+
+	assert := assert.New(t)
+
+	assert.Equal(200, code)
+	if assert.Len(got, 1) {
+		assert.Equal("messageid", got[0].MessageID)
+		assert.Equal(time.Date(2012, 1, 1, 12, 0, 5, 0, time.UTC), got[0].OccurredAt)
+		assert.Equal(&FailureReason{Code: 14, Description: "Unknown subscriber", Permanent: true}, got[0].FailureReason)
+	}
+}
+
+func TestNotificationHandlerInvalid(t *testing.T) {
+	h := NotificationHandler{}
+
+	assert := assert.New(t)
+
+	assert.Equal(200, postNotification(h, `<MessageDelivered><Id>a</Id></MessageDelivered>`))
+	assert.Equal(400, postNotification(h, `<SomethingElse />`))
+	assert.Equal(400, postNotification(h, `not xml`))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(405, w.Code)
+}
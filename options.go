@@ -1,34 +1,180 @@
 package esendex
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 )
 
-// Option is a function that mutates a request.
-type Option func(*http.Request)
+// Option sets query parameters on a request. An Option returns an error if it
+// is given invalid values, or sets a parameter already set by another Option;
+// the error is then returned by the method the Option was passed to.
+type Option func(*Params) error
+
+// Params collects the query parameters set by the Options passed to a method.
+// The zero value is empty and ready to use, so an Option can be inspected by
+// applying it to one, for example in tests using esendextest.Mock.
+type Params struct {
+	values url.Values
+}
+
+// Set sets the query parameter key to value. It returns an OptionError if key
+// has already been set.
+func (p *Params) Set(key, value string) error {
+	if _, ok := p.values[key]; ok {
+		return OptionError{Param: key, Reason: "is given more than once"}
+	}
+
+	if p.values == nil {
+		p.values = url.Values{}
+	}
+	p.values.Set(key, value)
+	return nil
+}
+
+// Get returns the value of the query parameter key, or "" if it is not set.
+func (p *Params) Get(key string) string {
+	return p.values.Get(key)
+}
+
+// OptionError is returned when an Option is invalid.
+type OptionError struct {
+	Param  string
+	Reason string
+}
+
+func (e OptionError) Error() string {
+	return fmt.Sprintf("esendex: option %s %s", e.Param, e.Reason)
+}
+
+// MessageDirection is whether a message was sent or received.
+type MessageDirection string
+
+const (
+	Inbound  MessageDirection = "IN"
+	Outbound MessageDirection = "OUT"
+)
+
+// SortOrder is the order that messages are returned in, by the time they were
+// submitted or received.
+type SortOrder string
+
+const (
+	Ascending  SortOrder = "asc"
+	Descending SortOrder = "desc"
+)
 
 // Page creates an option that sets the startindex and count query parameters.
+// The startIndex must not be negative, and count must be at least 1.
 func Page(startIndex, count int) Option {
-	return func(r *http.Request) {
-		q := r.URL.Query()
-
-		q.Add("startindex", strconv.Itoa(startIndex))
-		q.Add("count", strconv.Itoa(count))
+	return func(p *Params) error {
+		if startIndex < 0 {
+			return OptionError{Param: "startindex", Reason: "is negative"}
+		}
+		if count < 1 {
+			return OptionError{Param: "count", Reason: "is less than 1"}
+		}
 
-		r.URL.RawQuery = q.Encode()
+		if err := p.Set("startindex", strconv.Itoa(startIndex)); err != nil {
+			return err
+		}
+		return p.Set("count", strconv.Itoa(count))
 	}
 }
 
 // Between creates an option that sets the start and finish query parameters.
+// The finish must not be before start. Both are sent in UTC, whatever their
+// location.
 func Between(start, finish time.Time) Option {
-	return func(r *http.Request) {
-		q := r.URL.Query()
+	return func(p *Params) error {
+		if finish.Before(start) {
+			return OptionError{Param: "finish", Reason: "is before start"}
+		}
+
+		if err := p.Set("start", start.UTC().Format(time.RFC3339)); err != nil {
+			return err
+		}
+		return p.Set("finish", finish.UTC().Format(time.RFC3339))
+	}
+}
 
-		q.Add("start", start.Format(time.RFC3339))
-		q.Add("finish", finish.Format(time.RFC3339))
+// Status creates an option that only returns messages with the given status,
+// such as "Delivered" or "Failed".
+func Status(status string) Option {
+	return queryOption("status", status)
+}
+
+// Direction creates an option that only returns messages in the given
+// direction, which must be Inbound or Outbound.
+func Direction(direction MessageDirection) Option {
+	return func(p *Params) error {
+		if direction != Inbound && direction != Outbound {
+			return OptionError{Param: "direction", Reason: fmt.Sprintf("has unknown value %q", direction)}
+		}
 
-		r.URL.RawQuery = q.Encode()
+		return p.Set("direction", string(direction))
 	}
 }
+
+// To creates an option that only returns messages sent to the given number.
+func To(number string) Option {
+	return queryOption("to", number)
+}
+
+// From creates an option that only returns messages sent from the given
+// number or originator.
+func From(number string) Option {
+	return queryOption("from", number)
+}
+
+// Batch creates an option that only returns messages in the given batch.
+func Batch(id string) Option {
+	return queryOption("batchid", id)
+}
+
+// Type creates an option that only returns messages of the given type.
+func Type(messageType MessageType) Option {
+	return queryOption("type", string(messageType))
+}
+
+// Sort creates an option that sets the order messages are returned in, which
+// must be Ascending or Descending. The API returns the newest messages first
+// by default.
+func Sort(order SortOrder) Option {
+	return func(p *Params) error {
+		if order != Ascending && order != Descending {
+			return OptionError{Param: "sortorder", Reason: fmt.Sprintf("has unknown value %q", order)}
+		}
+
+		return p.Set("sortorder", string(order))
+	}
+}
+
+// queryOption returns an Option that sets key to value, which must not be
+// empty.
+func queryOption(key, value string) Option {
+	return func(p *Params) error {
+		if value == "" {
+			return OptionError{Param: key, Reason: "is empty"}
+		}
+
+		return p.Set(key, value)
+	}
+}
+
+// applyOptions applies the options to the request's query, returning the first
+// error.
+func applyOptions(req *http.Request, opts []Option) error {
+	p := &Params{values: req.URL.Query()}
+
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return err
+		}
+	}
+
+	req.URL.RawQuery = p.values.Encode()
+	return nil
+}
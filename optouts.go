@@ -0,0 +1,275 @@
+package esendex
+
+import (
+	"encoding/xml"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OptOutsResponse is a list of returned opt-outs along with the paging
+// information.
+type OptOutsResponse struct {
+	Paging
+	OptOuts []OptOutResponse
+}
+
+// OptOutResponse is a single opt-out, recording that messages should no longer
+// be sent to the number from the account.
+type OptOutResponse struct {
+	ID               string
+	URI              string
+	AccountReference string
+	ReceivedAt       time.Time
+	From             string
+}
+
+// OptOuts returns a list of the numbers that have opted out of receiving
+// messages from the account.
+func (c *AccountClient) OptOuts(opts ...Option) (*OptOutsResponse, error) {
+	accountOption := func(p *Params) error {
+		return p.Set("accountreference", c.reference)
+	}
+
+	req, err := c.newRequest("GET", "/v1.0/optouts", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyOptions(req, append(opts, accountOption)); err != nil {
+		return nil, err
+	}
+
+	var v optOutsResponse
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &OptOutsResponse{
+		Paging: Paging{
+			StartIndex: v.StartIndex,
+			Count:      v.Count,
+			TotalCount: v.TotalCount,
+		},
+		OptOuts: make([]OptOutResponse, len(v.OptOuts)),
+	}
+
+	for i, optOut := range v.OptOuts {
+		response.OptOuts[i] = OptOutResponse{
+			ID:               optOut.ID,
+			URI:              optOut.URI,
+			AccountReference: optOut.AccountReference,
+			ReceivedAt:       c.in(optOut.ReceivedAt.Time),
+			From:             optOut.From,
+		}
+	}
+
+	return response, nil
+}
+
+// AddOptOut records that the number has opted out of receiving messages from
+// the account.
+func (c *AccountClient) AddOptOut(number string) (*OptOutResponse, error) {
+	body := optOutRequest{
+		AccountReference: c.reference,
+		From:             number,
+	}
+
+	req, err := c.newRequest("POST", "/v1.0/optouts", &body)
+	if err != nil {
+		return nil, err
+	}
+
+	var v optOutsResponseOptOut
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	if c.OptOutFilter != nil {
+		c.OptOutFilter.add(v.From)
+	}
+
+	response := &OptOutResponse{
+		ID:               v.ID,
+		URI:              v.URI,
+		AccountReference: v.AccountReference,
+		ReceivedAt:       c.in(v.ReceivedAt.Time),
+		From:             v.From,
+	}
+
+	return response, nil
+}
+
+// RemoveOptOut deletes the opt-out with the given id, allowing messages to be
+// sent to the number again.
+func (c *AccountClient) RemoveOptOut(id string) error {
+	req, err := c.newRequest("DELETE", "/v1.0/optouts/"+id, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = c.do(req, nil); err != nil {
+		return err
+	}
+
+	if c.OptOutFilter != nil {
+		c.OptOutFilter.invalidate()
+	}
+
+	return nil
+}
+
+// OptOutError is the type of error returned when sending with an OptOutFilter
+// that has Reject set and one or more of the recipients have opted out.
+type OptOutError struct {
+	Numbers []string
+}
+
+func (e OptOutError) Error() string {
+	return "recipients have opted out: " + strings.Join(e.Numbers, ", ")
+}
+
+const optOutFilterPageSize = 100
+
+// OptOutFilter checks messages against a locally cached copy of an account's
+// opt-outs before they are sent. Assign one to AccountClient.OptOutFilter to
+// enable it.
+//
+// Messages sent to a Group are not checked, as the group's members are only
+// known to the API.
+type OptOutFilter struct {
+	// RefreshInterval is how long the cached opt-outs are used before being
+	// fetched again.
+	RefreshInterval time.Duration
+
+	// Reject causes sends that include an opted-out recipient to fail with an
+	// OptOutError. Otherwise those recipients are dropped and listed in
+	// SendResponse.OptedOut.
+	Reject bool
+
+	mu          sync.Mutex
+	numbers     map[string]struct{}
+	refreshedAt time.Time
+}
+
+// NewOptOutFilter returns a filter that refreshes its cached opt-outs every
+// interval.
+func NewOptOutFilter(interval time.Duration) *OptOutFilter {
+	return &OptOutFilter{RefreshInterval: interval}
+}
+
+// Refresh fetches every opt-out for the account, replacing the cached copy.
+func (f *OptOutFilter) Refresh(c *AccountClient) error {
+	numbers := map[string]struct{}{}
+
+	for startIndex := 0; ; startIndex += optOutFilterPageSize {
+		resp, err := c.OptOuts(Page(startIndex, optOutFilterPageSize))
+		if err != nil {
+			return err
+		}
+
+		for _, optOut := range resp.OptOuts {
+			numbers[normalizeNumber(optOut.From)] = struct{}{}
+		}
+
+		if len(resp.OptOuts) == 0 || startIndex+len(resp.OptOuts) >= resp.TotalCount {
+			break
+		}
+	}
+
+	f.mu.Lock()
+	f.numbers = numbers
+	f.refreshedAt = time.Now()
+	f.mu.Unlock()
+
+	return nil
+}
+
+// Contains reports whether the number is in the cached opt-outs.
+func (f *OptOutFilter) Contains(number string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.numbers[normalizeNumber(number)]
+	return ok
+}
+
+func (f *OptOutFilter) filter(c *AccountClient, messages []Message) ([]Message, []string, error) {
+	f.mu.Lock()
+	stale := f.numbers == nil || time.Since(f.refreshedAt) >= f.RefreshInterval
+	f.mu.Unlock()
+
+	if stale {
+		if err := f.Refresh(c); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var (
+		allowed  []Message
+		optedOut []string
+	)
+
+	for _, message := range messages {
+		if message.To != "" && f.Contains(message.To) {
+			optedOut = append(optedOut, message.To)
+		} else {
+			allowed = append(allowed, message)
+		}
+	}
+
+	if len(optedOut) > 0 && f.Reject {
+		return nil, nil, OptOutError{Numbers: optedOut}
+	}
+
+	return allowed, optedOut, nil
+}
+
+func (f *OptOutFilter) add(number string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.numbers != nil {
+		f.numbers[normalizeNumber(number)] = struct{}{}
+	}
+}
+
+func (f *OptOutFilter) invalidate() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.numbers = nil
+}
+
+// normalizeNumber strips everything but digits from a phone number so that, for
+// example, "+44 7700 900000" and "447700900000" compare equal.
+func normalizeNumber(number string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, number)
+}
+
+type optOutRequest struct {
+	XMLName          xml.Name `xml:"optout"`
+	AccountReference string   `xml:"accountreference"`
+	From             string   `xml:"from>phonenumber"`
+}
+
+type optOutsResponse struct {
+	XMLName    xml.Name                `xml:"http://api.esendex.com/ns/ optouts"`
+	StartIndex int                     `xml:"startindex,attr"`
+	Count      int                     `xml:"count,attr"`
+	TotalCount int                     `xml:"totalcount,attr"`
+	OptOuts    []optOutsResponseOptOut `xml:"optout"`
+}
+
+type optOutsResponseOptOut struct {
+	ID               string            `xml:"id,attr"`
+	URI              string            `xml:"uri,attr"`
+	AccountReference string            `xml:"accountreference"`
+	ReceivedAt       messageHeaderTime `xml:"receivedat"`
+	From             string            `xml:"from>phonenumber"`
+}
@@ -0,0 +1,238 @@
+package esendex
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleOptOutFilter() {
+	accountClient := New("user@example.com", "pass").Account("EX00000")
+	accountClient.OptOutFilter = NewOptOutFilter(time.Hour)
+
+	response, err := accountClient.Send([]Message{
+		{To: "00000000", Body: "Hello"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, number := range response.OptedOut {
+		log.Printf("not sent to %s as they have opted out", number)
+	}
+}
+
+func TestOptOuts(t *testing.T) {
+	const (
+		startIndex       = 0
+		count            = 15
+		totalCount       = 30
+		id               = "optoutid"
+		uri              = "http://someoptout"
+		accountReference = "EX0000000"
+		from             = "447700900000"
+	)
+
+	var (
+		receivedAt    = time.Date(2016, 1, 2, 13, 14, 15, 0, time.UTC)
+		receivedAtStr = "2016-01-02T13:14:15"
+	)
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<optouts startindex="`+strconv.Itoa(startIndex)+`" count="`+strconv.Itoa(count)+`" totalcount="`+strconv.Itoa(totalCount)+`" xmlns="http://api.esendex.com/ns/">
+ <optout id="`+id+`" uri="`+uri+`">
+  <accountreference>`+accountReference+`</accountreference>
+  <receivedat>`+receivedAtStr+`</receivedat>
+  <from>
+   <phonenumber>`+from+`</phonenumber>
+  </from>
+ </optout>
+</optouts>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	result, err := client.Account(accountReference).OptOuts()
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/optouts", h.Request.URL.Path)
+
+	if user, pass, ok := h.Request.BasicAuth(); assert.True(ok) {
+		assert.Equal("user", user)
+		assert.Equal("pass", pass)
+	}
+
+	query := h.Request.URL.Query()
+	assert.Equal(accountReference, query.Get("accountreference"))
+
+	assert.Equal(startIndex, result.StartIndex)
+	assert.Equal(count, result.Count)
+	assert.Equal(totalCount, result.TotalCount)
+
+	if assert.Equal(1, len(result.OptOuts)) {
+		optOut := result.OptOuts[0]
+
+		assert.Equal(id, optOut.ID)
+		assert.Equal(uri, optOut.URI)
+		assert.Equal(accountReference, optOut.AccountReference)
+		assert.Equal(receivedAt, optOut.ReceivedAt)
+		assert.Equal(from, optOut.From)
+	}
+}
+
+func TestAddOptOut(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<optout id="optoutid" uri="http://someoptout" xmlns="http://api.esendex.com/ns/">
+ <accountreference>EX0000000</accountreference>
+ <from>
+  <phonenumber>447700900000</phonenumber>
+ </from>
+</optout>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	optOut, err := client.Account("EX0000000").AddOptOut("447700900000")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("POST", h.Request.Method)
+	assert.Equal("/v1.0/optouts", h.Request.URL.String())
+	assert.Equal("<optout>"+
+		"<accountreference>EX0000000</accountreference>"+
+		"<from><phonenumber>447700900000</phonenumber></from>"+
+		"</optout>", h.RequestBody)
+
+	assert.Equal("optoutid", optOut.ID)
+	assert.Equal("447700900000", optOut.From)
+}
+
+func TestRemoveOptOut(t *testing.T) {
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	err := client.Account("EX0000000").RemoveOptOut("optoutid")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("DELETE", h.Request.Method)
+	assert.Equal("/v1.0/optouts/optoutid", h.Request.URL.String())
+}
+
+func newOptOutServer() (*httptest.Server, *recordingHandler, *recordingHandler) {
+	optOuts := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<optouts startindex="0" count="1" totalcount="1" xmlns="http://api.esendex.com/ns/">
+ <optout id="optoutid" uri="http://someoptout">
+  <from>
+   <phonenumber>447700900000</phonenumber>
+  </from>
+ </optout>
+</optouts>`, 200, map[string]string{})
+	dispatcher := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders batchid="batchid" xmlns="http://api.esendex.com/ns/">
+ <messageheader uri="messageuri" id="messageid" />
+</messageheaders>`, 200, map[string]string{})
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1.0/optouts", optOuts)
+	mux.Handle("/v1.0/messagedispatcher", dispatcher)
+
+	return httptest.NewServer(mux), optOuts, dispatcher
+}
+
+func TestSendWithOptOutFilter(t *testing.T) {
+	s, optOuts, dispatcher := newOptOutServer()
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	account := client.Account("EX0000000")
+	account.OptOutFilter = NewOptOutFilter(time.Hour)
+
+	result, err := account.Send([]Message{
+		{To: "+44 7700 900000", Body: "Hey"},
+		{To: "447700900001", Body: "Hey"},
+	})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("/v1.0/optouts", optOuts.Request.URL.Path)
+	assert.Equal("EX0000000", optOuts.Request.URL.Query().Get("accountreference"))
+
+	assert.Equal("<messages>"+
+		"<accountreference>EX0000000</accountreference>"+
+		"<message><to>447700900001</to><body>Hey</body></message>"+
+		"</messages>", dispatcher.RequestBody)
+
+	assert.Equal("batchid", result.BatchID)
+	assert.Equal([]string{"+44 7700 900000"}, result.OptedOut)
+}
+
+func TestSendWithOptOutFilterAllOptedOut(t *testing.T) {
+	s, _, dispatcher := newOptOutServer()
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	account := client.Account("EX0000000")
+	account.OptOutFilter = NewOptOutFilter(time.Hour)
+
+	result, err := account.Send([]Message{
+		{To: "447700900000", Body: "Hey"},
+	})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("", dispatcher.Request.Method)
+
+	assert.Equal("", result.BatchID)
+	assert.Equal([]string{"447700900000"}, result.OptedOut)
+}
+
+func TestSendWithOptOutFilterReject(t *testing.T) {
+	s, _, dispatcher := newOptOutServer()
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	account := client.Account("EX0000000")
+	account.OptOutFilter = &OptOutFilter{RefreshInterval: time.Hour, Reject: true}
+
+	_, err := account.Send([]Message{
+		{To: "447700900000", Body: "Hey"},
+		{To: "447700900001", Body: "Hey"},
+	})
+
+	assert := assert.New(t)
+
+	assert.Equal(OptOutError{Numbers: []string{"447700900000"}}, err)
+	assert.Equal("", dispatcher.Request.Method)
+}
@@ -0,0 +1,308 @@
+package esendex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+// phoneNumberElement matches the XML elements that carry phone numbers in
+// requests to, and responses from, the API: recipients, originators, account
+// addresses, contacts, opt-outs and notifications. Elements such as <from> that
+// only wrap a <phonenumber> are matched through it.
+var phoneNumberElement = regexp.MustCompile(`(?i)(<(?:phonenumber|to|from|mobilenumber|address)>)([^<]*)(</)`)
+
+// Interaction is a single request and the response returned for it.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request that is stored in a fixture.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the part of a response that is stored in a fixture.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that passes requests on to Transport and
+// records each request and response, so that they can be saved to a fixture
+// file and later served by a Replayer.
+//
+// Basic auth credentials and session ids are always redacted, so a replayed
+// session cannot be used against the API. Phone numbers in request and
+// response bodies, and in the query of requests filtered with To or From, are
+// redacted, leaving only their last three digits, unless KeepPhoneNumbers is
+// set.
+type Recorder struct {
+	// Transport makes the real requests, it defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	KeepPhoneNumbers bool
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns a Recorder that makes requests with the transport. If
+// transport is nil http.DefaultTransport is used.
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	return &Recorder{Transport: transport}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	reqBody, req, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    requestURL(req, r.KeepPhoneNumbers),
+			Header: redactHeader(req.Header),
+			Body:   redactBody(reqBody, r.KeepPhoneNumbers),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       redactSession(redactBody(string(data), r.KeepPhoneNumbers)),
+		},
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Interactions returns the interactions recorded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to a fixture file at path.
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Interactions(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// UnmatchedRequestError is the type of error returned by a Replayer when a
+// request does not match any of the remaining recorded interactions.
+type UnmatchedRequestError struct {
+	Method string
+	URL    string
+}
+
+func (e UnmatchedRequestError) Error() string {
+	return fmt.Sprintf("no recorded interaction for %s %s", e.Method, e.URL)
+}
+
+// Replayer is an http.RoundTripper that serves responses from a fixture file
+// written by a Recorder, without making any real requests.
+//
+// A request matches an interaction when its method, path, query and body
+// (redacted in the same way as when recording) are equal. Each interaction is
+// served at most once, in the order they were recorded.
+type Replayer struct {
+	keepPhoneNumbers bool
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer loads the fixture file at path. If the fixture was recorded with
+// KeepPhoneNumbers set, keepPhoneNumbers must also be true.
+func NewReplayer(path string, keepPhoneNumbers bool) (*Replayer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		keepPhoneNumbers: keepPhoneNumbers,
+		interactions:     interactions,
+		used:             make([]bool, len(interactions)),
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, _, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := requestURL(req, r.keepPhoneNumbers)
+	body := redactBody(reqBody, r.keepPhoneNumbers)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] {
+			continue
+		}
+
+		if interaction.Request.Method == req.Method &&
+			interaction.Request.URL == reqURL &&
+			interaction.Request.Body == body {
+			r.used[i] = true
+
+			header := interaction.Response.Header
+			if header == nil {
+				header = http.Header{}
+			}
+
+			return &http.Response{
+				Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+				StatusCode:    interaction.Response.StatusCode,
+				Proto:         "HTTP/1.1",
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        header,
+				Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+				ContentLength: int64(len(interaction.Response.Body)),
+				Request:       req,
+			}, nil
+		}
+	}
+
+	return nil, UnmatchedRequestError{Method: req.Method, URL: reqURL}
+}
+
+// Unused returns the interactions that have not yet been served. A test can
+// check this is empty to ensure every recorded request was made.
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+
+	return unused
+}
+
+// readRequestBody reads and closes the body of the request, returning it along
+// with a copy of the request that can be sent on with the same body.
+func readRequestBody(req *http.Request) (string, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", req, nil
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", nil, err
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	return string(data), clone, nil
+}
+
+// requestURL returns the path and sorted query of the request, so that fixtures
+// do not depend on the host they were recorded against. The numbers given to
+// the To and From options are redacted unless keepPhoneNumbers is set.
+func requestURL(req *http.Request, keepPhoneNumbers bool) string {
+	u := req.URL.EscapedPath()
+	if q := req.URL.Query(); len(q) > 0 {
+		if !keepPhoneNumbers {
+			for _, key := range []string{"to", "from"} {
+				for i, value := range q[key] {
+					q[key][i] = maskNumber(value)
+				}
+			}
+		}
+
+		u += "?" + q.Encode()
+	}
+
+	return u
+}
+
+func redactHeader(header http.Header) http.Header {
+	h := http.Header{}
+	for key, values := range header {
+		if strings.EqualFold(key, "Authorization") {
+			h.Set(key, redacted)
+			continue
+		}
+
+		h[key] = values
+	}
+
+	return h
+}
+
+func redactBody(body string, keepPhoneNumbers bool) string {
+	if keepPhoneNumbers {
+		return body
+	}
+
+	return phoneNumberElement.ReplaceAllStringFunc(body, func(element string) string {
+		parts := phoneNumberElement.FindStringSubmatch(element)
+		return parts[1] + maskNumber(parts[2]) + parts[3]
+	})
+}
+
+// maskNumber replaces all but the last three characters of a number with X.
+func maskNumber(number string) string {
+	if len(number) <= 3 {
+		return number
+	}
+
+	return strings.Repeat("X", len(number)-3) + number[len(number)-3:]
+}
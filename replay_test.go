@@ -0,0 +1,282 @@
+package esendex
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleRecorder() {
+	recorder := NewRecorder(nil)
+
+	client := New("user@example.com", "pass")
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	client.Account("EX00000").Received()
+
+	recorder.Save("testdata/received.json")
+}
+
+func ExampleReplayer() {
+	replayer, err := NewReplayer("testdata/received.json", false)
+	if err != nil {
+		return
+	}
+
+	client := New("user@example.com", "pass")
+	client.HTTPClient = &http.Client{Transport: replayer}
+
+	client.Account("EX00000").Received()
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders batchid="batchid" xmlns="http://api.esendex.com/ns/">
+  <messageheader uri="messageuri" id="messageid" />
+</messageheaders>`, 200, map[string]string{"Content-Type": "application/xml"})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	assert := assert.New(t)
+
+	recorder := NewRecorder(nil)
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	messages := []Message{{To: "447700900123", Body: "Hello"}}
+
+	recorded, err := client.Account("EX0000000").Send(messages)
+	assert.Nil(err)
+
+	assert.Equal("<messages>"+
+		"<accountreference>EX0000000</accountreference>"+
+		"<message><to>447700900123</to><body>Hello</body></message>"+
+		"</messages>", h.RequestBody)
+
+	if interactions := recorder.Interactions(); assert.Len(interactions, 1) {
+		request := interactions[0].Request
+
+		assert.Equal("POST", request.Method)
+		assert.Equal("/v1.0/messagedispatcher", request.URL)
+		assert.Equal(redacted, request.Header.Get("Authorization"))
+		assert.Contains(request.Body, "<to>XXXXXXXXX123</to>")
+		assert.NotContains(request.Body, "447700900123")
+
+		assert.Equal(200, interactions[0].Response.StatusCode)
+		assert.Contains(interactions[0].Response.Body, `batchid="batchid"`)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	assert.Nil(recorder.Save(path))
+
+	replayer, err := NewReplayer(path, false)
+	if !assert.Nil(err) {
+		return
+	}
+
+	client = New("other", "credentials")
+	client.BaseURL, _ = url.Parse("http://replay.invalid")
+	client.HTTPClient = &http.Client{Transport: replayer}
+
+	replayed, err := client.Account("EX0000000").Send(messages)
+	if assert.Nil(err) {
+		assert.Equal(recorded, replayed)
+	}
+	assert.Empty(replayer.Unused())
+
+	_, err = client.Account("EX0000000").Send(messages)
+
+	var unmatched UnmatchedRequestError
+	if assert.True(errors.As(err, &unmatched)) {
+		assert.Equal("POST", unmatched.Method)
+		assert.Equal("/v1.0/messagedispatcher", unmatched.URL)
+	}
+}
+
+func TestReplayMatchesQuery(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messagebatches startindex="4" count="0" totalcount="200" xmlns="http://api.esendex.com/ns/">
+</messagebatches>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	assert := assert.New(t)
+
+	recorder := NewRecorder(nil)
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	_, err := client.Account("EXWHAT").Batches(Page(4, 10))
+	assert.Nil(err)
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	assert.Nil(recorder.Save(path))
+
+	replayer, err := NewReplayer(path, false)
+	if !assert.Nil(err) {
+		return
+	}
+
+	client.HTTPClient = &http.Client{Transport: replayer}
+
+	_, err = client.Account("EXWHAT").Batches(Page(5, 10))
+	if assert.NotNil(err) {
+		assert.True(strings.Contains(err.Error(), "no recorded interaction"))
+	}
+	assert.Len(replayer.Unused(), 1)
+
+	resp, err := client.Account("EXWHAT").Batches(Page(4, 10))
+	if assert.Nil(err) {
+		assert.Equal(200, resp.TotalCount)
+	}
+	assert.Empty(replayer.Unused())
+}
+
+func TestRecorderRedactsPhoneNumbers(t *testing.T) {
+	testCases := map[string]string{
+		"<address>447700900000</address>":                           "<address>XXXXXXXXX000</address>",
+		"<from>447700900555</from>":                                 "<from>XXXXXXXXX555</from>",
+		"<from>\n <phonenumber>447700900555</phonenumber>\n</from>": "<from>\n <phonenumber>XXXXXXXXX555</phonenumber>\n</from>",
+		"<mobilenumber>447700900111</mobilenumber>":                 "<mobilenumber>XXXXXXXXX111</mobilenumber>",
+		"<From>447700900222</From><To>447700900333</To>":            "<From>XXXXXXXXX222</From><To>XXXXXXXXX333</To>",
+		"<emailaddress>user@example.com</emailaddress>":             "<emailaddress>user@example.com</emailaddress>",
+	}
+
+	for body, expected := range testCases {
+		assert.Equal(t, expected, redactBody(body, false))
+		assert.Equal(t, body, redactBody(body, true))
+	}
+}
+
+func TestRecorderRedactsAccountsAndOriginators(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<accounts xmlns="http://api.esendex.com/ns/">
+ <account id="accountid"><reference>EX0000000</reference><address>447700900000</address></account>
+</accounts>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	recorder := NewRecorder(nil)
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	client.Accounts()
+	client.Account("EX0000000").SendFrom("447700900555", []Message{{To: "447700900123", Body: "Hello"}})
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	assert.Nil(t, recorder.Save(path))
+
+	data, _ := ioutil.ReadFile(path)
+	for _, number := range []string{"447700900000", "447700900555", "447700900123"} {
+		assert.NotContains(t, string(data), number)
+	}
+}
+
+func TestRecorderRedactsQueryNumbers(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders startindex="0" count="0" totalcount="0" xmlns="http://api.esendex.com/ns/" />`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	recorder := NewRecorder(nil)
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	_, err := client.Sent(To("447700900123"), From("447700900555"))
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("447700900123", h.Request.URL.Query().Get("to"))
+
+	if interactions := recorder.Interactions(); assert.Len(interactions, 1) {
+		assert.Equal("/v1.0/messageheaders?from=XXXXXXXXX555&to=XXXXXXXXX123", interactions[0].Request.URL)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	assert.Nil(recorder.Save(path))
+
+	data, _ := ioutil.ReadFile(path)
+	assert.NotContains(string(data), "447700900123")
+	assert.NotContains(string(data), "447700900555")
+
+	replayer, err := NewReplayer(path, false)
+	if !assert.Nil(err) {
+		return
+	}
+
+	client.HTTPClient = &http.Client{Transport: replayer}
+
+	_, err = client.Sent(To("447700900123"), From("447700900555"))
+	assert.Nil(err)
+	assert.Empty(replayer.Unused())
+
+	_, err = client.Sent(To("447700900999"))
+
+	var unmatched UnmatchedRequestError
+	if assert.True(errors.As(err, &unmatched)) {
+		assert.Equal("/v1.0/messageheaders?to=XXXXXXXXX999", unmatched.URL)
+	}
+}
+
+func TestRecorderRedactsSession(t *testing.T) {
+	const id = "f0b9ad71-d9f5-4de7-9b8a-0a6b8bd0e3a4"
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<session xmlns="http://api.esendex.com/ns/">
+ <id>`+id+`</id>
+</session>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	recorder := NewRecorder(nil)
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.HTTPClient = &http.Client{Transport: recorder}
+
+	session, err := client.CreateSession()
+
+	assert := assert.New(t)
+
+	if assert.Nil(err) {
+		assert.Equal(id, session)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	assert.Nil(recorder.Save(path))
+
+	data, _ := ioutil.ReadFile(path)
+	assert.NotContains(string(data), id)
+
+	if interactions := recorder.Interactions(); assert.Len(interactions, 1) {
+		assert.Contains(interactions[0].Response.Body, "<id>"+redacted+"</id>")
+	}
+
+	replayer, err := NewReplayer(path, false)
+	if !assert.Nil(err) {
+		return
+	}
+
+	client.HTTPClient = &http.Client{Transport: replayer}
+
+	session, err = client.CreateSession()
+	if assert.Nil(err) {
+		assert.Equal(redacted, session)
+	}
+}
@@ -0,0 +1,155 @@
+package esendex
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Sandbox stops a Client sending messages to anyone other than an allowed set
+// of numbers, for use in development and staging environments.
+//
+// Messages to other numbers, and to groups, are validated and logged with the
+// Client's Logger, but not sent. They are given generated ids in the
+// SendResponse, so code handling the response keeps working. Note that
+// requests using these ids, such as Message, will fail.
+type Sandbox struct {
+	// Allow lists the numbers messages may really be sent to. An entry ending
+	// in * allows any number starting with it. Numbers are compared using only
+	// their digits.
+	Allow []string
+}
+
+// ValidationError is returned when a Sandbox rejects a message that the API
+// would not accept.
+type ValidationError struct {
+	// Index is the position of the message in the list passed to Send.
+	Index  int
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("esendex: message %d %s", e.Index, e.Reason)
+}
+
+func (s *Sandbox) allows(message Message) bool {
+	if message.Group != "" {
+		return false
+	}
+
+	number := normalizeNumber(message.To)
+
+	for _, allowed := range s.Allow {
+		if prefix := strings.TrimSuffix(allowed, "*"); prefix != allowed {
+			if strings.HasPrefix(number, normalizeNumber(prefix)) {
+				return true
+			}
+		} else if number == normalizeNumber(allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateMessage(message Message) string {
+	switch {
+	case message.To == "" && message.Group == "":
+		return "has no recipient"
+	case message.To != "" && message.Group != "":
+		return "has both a recipient and a group"
+	case message.Body == "":
+		return "has no body"
+	case message.MessageType != "" && message.MessageType != SMS && message.MessageType != Voice:
+		return fmt.Sprintf("has unknown type %q", message.MessageType)
+	case message.Validity < 0 || message.Validity > 72:
+		return "has a validity outside of 0 to 72 hours"
+	}
+
+	return ""
+}
+
+// split validates the messages, returning those that may really be sent along
+// with their positions in messages.
+func (s *Sandbox) split(messages []Message) ([]Message, []int, error) {
+	var (
+		allowed   []Message
+		positions []int
+	)
+
+	for i, message := range messages {
+		if reason := validateMessage(message); reason != "" {
+			return nil, nil, ValidationError{Index: i, Reason: reason}
+		}
+
+		if s.allows(message) {
+			allowed = append(allowed, message)
+			positions = append(positions, i)
+		}
+	}
+
+	return allowed, positions, nil
+}
+
+// sandboxResponse returns the response for all of the messages, taking the
+// details of those at positions from sent, which is nil if none were sent, and
+// generating them for the rest.
+func (c *AccountClient) sandboxResponse(ctx context.Context, all []Message, positions []int, sent *SendResponse) *SendResponse {
+	response := &SendResponse{
+		Messages: make([]SendResponseMessage, len(all)),
+	}
+
+	if sent != nil {
+		response.BatchID = sent.BatchID
+		response.OptedOut = sent.OptedOut
+	} else {
+		response.BatchID = sandboxID()
+	}
+
+	j := 0
+	for i, message := range all {
+		if j < len(positions) && positions[j] == i {
+			if sent != nil && j < len(sent.Messages) {
+				response.Messages[i] = sent.Messages[j]
+			}
+			j++
+			continue
+		}
+
+		id := sandboxID()
+		response.Messages[i] = SendResponseMessage{ID: id}
+		if u, err := c.BaseURL.Parse("/v1.0/messageheaders/" + id); err == nil {
+			response.Messages[i].URI = u.String()
+		}
+
+		if c.Logger != nil {
+			to := message.To
+			if !c.LogOptions.KeepPhoneNumbers {
+				to = maskNumber(to)
+			}
+
+			c.Logger.LogAttrs(ctx, slog.LevelInfo, "esendex sandbox send",
+				slog.String("account_reference", c.reference),
+				slog.String("to", to),
+				slog.String("group", message.Group),
+				slog.Int("segments", Segments(message.Body)),
+				slog.String("batch_id", response.BatchID),
+				slog.String("message_id", id),
+			)
+		}
+	}
+
+	return response
+}
+
+// sandboxID returns a random id in the same form as those generated by the API.
+func sandboxID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
@@ -0,0 +1,117 @@
+package esendex
+
+import (
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandbox(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders batchid="batchid" xmlns="http://api.esendex.com/ns/">
+ <messageheader uri="http://somemessage" id="messageid" />
+</messageheaders>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client, buf := newLoggingClient(s.URL, slog.LevelInfo)
+	client.Sandbox = &Sandbox{Allow: []string{"+44 7700 900123"}}
+
+	resp, err := client.Account("EX00000").Send([]Message{
+		{To: "447700900999", Body: "Hello"},
+		{To: "447700900123", Body: "Hello"},
+		{Group: "group", Body: "Hello"},
+	})
+
+	assert := assert.New(t)
+
+	if assert.Nil(err) {
+		assert.Equal("batchid", resp.BatchID)
+
+		if assert.Len(resp.Messages, 3) {
+			assert.NotEmpty(resp.Messages[0].ID)
+			assert.Equal(s.URL+"/v1.0/messageheaders/"+resp.Messages[0].ID, resp.Messages[0].URI)
+			assert.Equal(SendResponseMessage{ID: "messageid", URI: "http://somemessage"}, resp.Messages[1])
+			assert.NotEmpty(resp.Messages[2].ID)
+			assert.NotEqual(resp.Messages[0].ID, resp.Messages[2].ID)
+		}
+	}
+
+	assert.Contains(h.RequestBody, "<to>447700900123</to>")
+	assert.NotContains(h.RequestBody, "447700900999")
+	assert.NotContains(h.RequestBody, "group")
+
+	records := logRecords(buf)
+	if assert.Len(records, 3) {
+		assert.Equal("esendex sandbox send", records[1]["msg"])
+		assert.Equal("XXXXXXXXX999", records[1]["to"])
+		assert.Equal("esendex sandbox send", records[2]["msg"])
+		assert.Equal("group", records[2]["group"])
+	}
+}
+
+func TestSandboxWhenNothingAllowed(t *testing.T) {
+	h := newRecordingHandler("", 500, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.Sandbox = &Sandbox{}
+
+	resp, err := client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Hello"}})
+
+	assert := assert.New(t)
+
+	if assert.Nil(err) {
+		assert.Len(resp.BatchID, 36)
+		if assert.Len(resp.Messages, 1) {
+			assert.Len(resp.Messages[0].ID, 36)
+		}
+	}
+
+	assert.Empty(h.Request.Method)
+}
+
+func TestSandboxAllowsPrefix(t *testing.T) {
+	sandbox := &Sandbox{Allow: []string{"+44 7700 900*"}}
+
+	assert.True(t, sandbox.allows(Message{To: "447700900123"}))
+	assert.False(t, sandbox.allows(Message{To: "447700800123"}))
+	assert.False(t, sandbox.allows(Message{Group: "447700900123"}))
+}
+
+func TestSandboxWhenMessageInvalid(t *testing.T) {
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.Sandbox = &Sandbox{Allow: []string{"*"}}
+
+	testCases := map[string]Message{
+		"has no recipient":                        {Body: "Hello"},
+		"has both a recipient and a group":        {To: "447700900123", Group: "group", Body: "Hello"},
+		"has no body":                             {To: "447700900123"},
+		`has unknown type "Fax"`:                  {To: "447700900123", Body: "Hello", MessageType: "Fax"},
+		"has a validity outside of 0 to 72 hours": {To: "447700900123", Body: "Hello", Validity: 73},
+	}
+
+	for reason, message := range testCases {
+		_, err := client.Account("EX00000").Send([]Message{{To: "447700900123", Body: "Hello"}, message})
+
+		var verr ValidationError
+		if assert.True(t, errors.As(err, &verr), reason) {
+			assert.Equal(t, ValidationError{Index: 1, Reason: reason}, verr)
+			assert.True(t, strings.HasPrefix(err.Error(), "esendex: message 1 "))
+		}
+	}
+
+	assert.Empty(t, h.Request.Method)
+}
@@ -0,0 +1,52 @@
+package esendex
+
+import "strings"
+
+// gsmCharacters are the characters in the GSM 03.38 default alphabet.
+const gsmCharacters = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsmExtensionCharacters are the characters in the GSM 03.38 extension table,
+// which take two characters each to send.
+const gsmExtensionCharacters = "\f^{}\\[~]|€"
+
+// Segments returns the number of SMS parts needed to send the body. Bodies
+// using only the GSM character set fit 160 characters in a single part or 153
+// per part when split, otherwise 70 and 67 are used.
+func Segments(body string) int {
+	if body == "" {
+		return 1
+	}
+
+	length, gsm := 0, true
+	for _, r := range body {
+		switch {
+		case strings.ContainsRune(gsmCharacters, r):
+			length++
+		case strings.ContainsRune(gsmExtensionCharacters, r):
+			length += 2
+		default:
+			gsm = false
+		}
+	}
+
+	single, multi := 160, 153
+	if !gsm {
+		single, multi = 70, 67
+
+		length = 0
+		for _, r := range body {
+			if r > 0xFFFF {
+				length += 2
+			} else {
+				length++
+			}
+		}
+	}
+
+	if length <= single {
+		return 1
+	}
+
+	return (length + multi - 1) / multi
+}
@@ -0,0 +1,35 @@
+package esendex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegments(t *testing.T) {
+	testCases := []struct {
+		body     string
+		segments int
+	}{
+		{"", 1},
+		{"Hello", 1},
+		{strings.Repeat("a", 160), 1},
+		{strings.Repeat("a", 161), 2},
+		{strings.Repeat("a", 306), 2},
+		{strings.Repeat("a", 307), 3},
+		{strings.Repeat("€", 80), 1},
+		{strings.Repeat("€", 81), 2},
+		{strings.Repeat("é", 160), 1},
+		{strings.Repeat("ć", 70), 1},
+		{strings.Repeat("ć", 71), 2},
+		{strings.Repeat("ć", 134), 2},
+		{strings.Repeat("ć", 135), 3},
+		{strings.Repeat("😀", 35), 1},
+		{strings.Repeat("😀", 36), 2},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.segments, Segments(tc.body), "%q", tc.body)
+	}
+}
@@ -1,6 +1,7 @@
 package esendex
 
 import (
+	"context"
 	"encoding/xml"
 	"time"
 )
@@ -13,8 +14,12 @@ const (
 )
 
 // Message is a message to send.
+//
+// Group may be set to the id of a contact group instead of setting To, in which
+// case the message is sent to every member of the group.
 type Message struct {
 	To           string
+	Group        string
 	From         string
 	MessageType  MessageType
 	Lang         string
@@ -26,9 +31,16 @@ type Message struct {
 
 // SendResponse gives the batchid for the sent batch and lists the details of
 // each message sent.
+//
+// OptedOut lists the recipients that were dropped by the AccountClient's
+// OptOutFilter. If every recipient was dropped nothing is sent and BatchID is
+// empty. Dropped messages have no entry in Messages, so when any are dropped
+// Messages is shorter than, and no longer lines up with, the messages passed to
+// Send: it has an entry for each remaining message, in the order given.
 type SendResponse struct {
 	BatchID  string
 	Messages []SendResponseMessage
+	OptedOut []string
 }
 
 // SendResponseMessage gives the details for a single sent message.
@@ -39,38 +51,113 @@ type SendResponseMessage struct {
 
 // Send dispatches a list of messages.
 func (c *AccountClient) Send(messages []Message) (*SendResponse, error) {
+	return c.SendContext(context.Background(), messages)
+}
+
+// SendContext dispatches a list of messages. If the Client has a Tracer the
+// span for the send is a child of any span in ctx.
+func (c *AccountClient) SendContext(ctx context.Context, messages []Message) (*SendResponse, error) {
 	body := messageDispatchRequest{
 		AccountReference: c.reference,
 		Message:          make([]messageDispatchRequestMessage, len(messages)),
 	}
 
-	return c.doSend(body, messages)
+	return c.doSend(ctx, body, messages)
 }
 
 // SendFrom dispatches a list of messages and overrides the default originator.
 func (c *AccountClient) SendFrom(from string, messages []Message) (*SendResponse, error) {
+	return c.SendFromContext(context.Background(), from, messages)
+}
+
+// SendFromContext is SendFrom with a context, as for SendContext.
+func (c *AccountClient) SendFromContext(ctx context.Context, from string, messages []Message) (*SendResponse, error) {
 	body := messageDispatchRequest{
 		AccountReference: c.reference,
 		From:             from,
 		Message:          make([]messageDispatchRequestMessage, len(messages)),
 	}
 
-	return c.doSend(body, messages)
+	return c.doSend(ctx, body, messages)
 }
 
 // SendAt schedules a list of messages for dispatch.
 func (c *AccountClient) SendAt(sendAt time.Time, messages []Message) (*SendResponse, error) {
+	return c.SendAtContext(context.Background(), sendAt, messages)
+}
+
+// SendAtContext is SendAt with a context, as for SendContext.
+func (c *AccountClient) SendAtContext(ctx context.Context, sendAt time.Time, messages []Message) (*SendResponse, error) {
 	body := messageDispatchRequest{
 		AccountReference: c.reference,
 		SendAt:           &sendAt,
 		Message:          make([]messageDispatchRequestMessage, len(messages)),
 	}
 
-	return c.doSend(body, messages)
+	return c.doSend(ctx, body, messages)
+}
+
+func (c *AccountClient) doSend(ctx context.Context, body messageDispatchRequest, messages []Message) (*SendResponse, error) {
+	ctx, span := c.startSpan(ctx, "esendex.Send")
+	defer span.End()
+
+	span.SetAttribute("esendex.account_reference", c.reference)
+
+	response, err := c.dispatch(ctx, span, body, messages)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return response, err
 }
 
-func (c *AccountClient) doSend(body messageDispatchRequest, messages []Message) (*SendResponse, error) {
+func (c *AccountClient) dispatch(ctx context.Context, span Span, body messageDispatchRequest, messages []Message) (*SendResponse, error) {
+	var optedOut []string
+
+	if c.OptOutFilter != nil {
+		var err error
+		if messages, optedOut, err = c.OptOutFilter.filter(c, messages); err != nil {
+			return nil, err
+		}
+
+		span.SetAttribute("esendex.opted_out_count", len(optedOut))
+
+		if len(messages) == 0 {
+			span.SetAttribute("esendex.message_count", 0)
+			return &SendResponse{OptedOut: optedOut}, nil
+		}
+	}
+
+	var (
+		all       []Message
+		positions []int
+	)
+
+	if c.Sandbox != nil {
+		var err error
+		all = messages
+		if messages, positions, err = c.Sandbox.split(all); err != nil {
+			return nil, err
+		}
+
+		span.SetAttribute("esendex.sandboxed_count", len(all)-len(messages))
+
+		if len(messages) == 0 {
+			span.SetAttribute("esendex.message_count", 0)
+
+			response := c.sandboxResponse(ctx, all, positions, nil)
+			response.OptedOut = optedOut
+			return response, nil
+		}
+	}
+
+	body.Message = make([]messageDispatchRequestMessage, len(messages))
+	span.SetAttribute("esendex.message_count", len(messages))
+
+	segments := 0
 	for i, message := range messages {
+		segments += Segments(message.Body)
+
 		body.Message[i] = messageDispatchRequestMessage{
 			To:           message.To,
 			From:         message.From,
@@ -81,21 +168,33 @@ func (c *AccountClient) doSend(body messageDispatchRequest, messages []Message)
 			Retries:      message.Retries,
 			Body:         message.Body,
 		}
+
+		if message.Group != "" {
+			body.Message[i].Group = &messageDispatchRequestGroup{ID: message.Group}
+		}
 	}
 
 	req, err := c.newRequest("POST", "/v1.0/messagedispatcher", &body)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	var v messageDispatchResponse
-	if _, err = c.do(req, &v); err != nil {
+	if _, err = c.doCall(&Call{Request: req, Messages: messages}, &v); err != nil {
 		return nil, err
 	}
 
+	span.SetAttribute("esendex.batch_id", v.BatchID)
+
+	if c.Metrics != nil {
+		c.Metrics.MessagesDispatched(c.reference, len(messages), segments)
+	}
+
 	response := &SendResponse{
 		BatchID:  v.BatchID,
 		Messages: make([]SendResponseMessage, len(v.MessageHeader)),
+		OptedOut: optedOut,
 	}
 
 	for i, message := range v.MessageHeader {
@@ -105,6 +204,10 @@ func (c *AccountClient) doSend(body messageDispatchRequest, messages []Message)
 		}
 	}
 
+	if c.Sandbox != nil {
+		response = c.sandboxResponse(ctx, all, positions, response)
+	}
+
 	return response, nil
 }
 
@@ -117,14 +220,19 @@ type messageDispatchRequest struct {
 }
 
 type messageDispatchRequestMessage struct {
-	To           string `xml:"to"`
-	From         string `xml:"from,omitempty"`
-	MessageType  string `xml:"type,omitempty"`
-	Lang         string `xml:"lang,omitempty"`
-	Validity     int    `xml:"validity,omitempty"`
-	CharacterSet string `xml:"characterset,omitempty"`
-	Retries      int    `xml:"retries,omitempty"`
-	Body         string `xml:"body"`
+	To           string                       `xml:"to,omitempty"`
+	Group        *messageDispatchRequestGroup `xml:"group"`
+	From         string                       `xml:"from,omitempty"`
+	MessageType  string                       `xml:"type,omitempty"`
+	Lang         string                       `xml:"lang,omitempty"`
+	Validity     int                          `xml:"validity,omitempty"`
+	CharacterSet string                       `xml:"characterset,omitempty"`
+	Retries      int                          `xml:"retries,omitempty"`
+	Body         string                       `xml:"body"`
+}
+
+type messageDispatchRequestGroup struct {
+	ID string `xml:"id,attr"`
 }
 
 type messageDispatchResponse struct {
@@ -229,3 +229,53 @@ func TestSendAtSingleMessage(t *testing.T) {
 	assert.Equal(messageID, result.Messages[0].ID)
 	assert.Equal(messageURI, result.Messages[0].URI)
 }
+
+func TestSendToGroup(t *testing.T) {
+	const (
+		batchID          = "batchID"
+		messageID        = "messageID"
+		messageURI       = "messageURI"
+		accountReference = "EXWHATEVS"
+		groupID          = "groupID"
+		body             = "HWEYERW"
+	)
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheaders batchid="`+batchID+`" xmlns="http://api.esendex.com/ns/">
+  <messageheader uri="`+messageURI+`" id="`+messageID+`" />
+</messageheaders>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	account := client.Account(accountReference)
+
+	result, err := account.Send([]Message{
+		{
+			Group: groupID,
+			Body:  body,
+		},
+	})
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("POST", h.Request.Method)
+	assert.Equal("/v1.0/messagedispatcher", h.Request.URL.String())
+
+	var expectedBodyStr = fmt.Sprintf("<messages>"+
+		"<accountreference>%s</accountreference>"+
+		"<message>"+
+		`<group id="%s"></group>`+
+		"<body>%s</body>"+
+		"</message>"+
+		"</messages>",
+		accountReference, groupID, body)
+	assert.Equal(expectedBodyStr, h.RequestBody)
+
+	assert.Equal(batchID, result.BatchID)
+	assert.Equal(1, len(result.Messages))
+}
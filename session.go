@@ -0,0 +1,46 @@
+package esendex
+
+import (
+	"encoding/xml"
+	"regexp"
+)
+
+// sessionIDElement matches the id in a session response, which is a credential.
+var sessionIDElement = regexp.MustCompile(`(?s)(<session\b[^>]*>.*?<id>)([^<]*)(</id>)`)
+
+// NewSession returns a new API client that authenticates with a session id
+// created by CreateSession, rather than a username and password. Sessions
+// expire after a period of inactivity.
+func NewSession(id string) *Client {
+	client := New("", "")
+	client.session = id
+
+	return client
+}
+
+// CreateSession authenticates with the client's credentials and returns the id
+// of a new session, which can be used with NewSession.
+func (c *Client) CreateSession() (string, error) {
+	req, err := c.newRequest("POST", "/v1.0/session/constructor", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var v sessionResponse
+	if _, err := c.do(req, &v); err != nil {
+		return "", err
+	}
+
+	return v.ID, nil
+}
+
+type sessionResponse struct {
+	XMLName xml.Name `xml:"http://api.esendex.com/ns/ session"`
+	ID      string   `xml:"id"`
+}
+
+// redactSession replaces any session id in body, so that it is not stored or
+// logged.
+func redactSession(body string) string {
+	return sessionIDElement.ReplaceAllString(body, "${1}"+redacted+"${3}")
+}
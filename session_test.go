@@ -0,0 +1,59 @@
+package esendex
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSession(t *testing.T) {
+	const id = "f0b9ad71-d9f5-4de7-9b8a-0a6b8bd0e3a4"
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<session xmlns="http://api.esendex.com/ns/">
+ <id>`+id+`</id>
+</session>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	result, err := client.CreateSession()
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("POST", h.Request.Method)
+	assert.Equal("/v1.0/session/constructor", h.Request.URL.String())
+
+	if user, pass, ok := h.Request.BasicAuth(); assert.True(ok) {
+		assert.Equal("user", user)
+		assert.Equal("pass", pass)
+	}
+
+	assert.Equal(id, result)
+}
+
+func TestNewSession(t *testing.T) {
+	const id = "f0b9ad71-d9f5-4de7-9b8a-0a6b8bd0e3a4"
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<accounts xmlns="http://api.esendex.com/ns/" />`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := NewSession(id)
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	_, err := client.Accounts()
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("Basic "+base64.StdEncoding.EncodeToString([]byte(id)), h.Request.Header.Get("Authorization"))
+}
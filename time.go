@@ -0,0 +1,71 @@
+package esendex
+
+import (
+	"strings"
+	"time"
+)
+
+// The API returns times in several layouts: with an offset, with a Z, or with
+// no zone at all, in which case they are UTC. Fields with no value are either
+// empty or hold the zero time.
+//
+// All times are parsed to the instant they describe and returned in UTC, or in
+// Client.Location if set. Fields with no value are always the zero time.Time,
+// so can be checked with IsZero.
+var apiTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+}
+
+// parseTime parses a time returned by the API.
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	var (
+		t   time.Time
+		err error
+	)
+
+	for _, layout := range apiTimeLayouts {
+		if t, err = time.ParseInLocation(layout, s, time.UTC); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if t.IsZero() {
+		return time.Time{}, nil
+	}
+
+	return t.UTC(), nil
+}
+
+// formatTime formats a time as the API does, in UTC. The zero time is formatted
+// as an empty string.
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(layout)
+}
+
+// inLocation returns t in loc, or unchanged if loc is nil. The zero time is
+// always returned unchanged.
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	if loc == nil || t.IsZero() {
+		return t
+	}
+
+	return t.In(loc)
+}
+
+// in returns t in the Client's Location.
+func (c *Client) in(t time.Time) time.Time {
+	return inLocation(t, c.Location)
+}
@@ -0,0 +1,204 @@
+package esendex
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTime(t *testing.T) {
+	testCases := map[string]time.Time{
+		"2012-01-01T12:00:05":           time.Date(2012, 1, 1, 12, 0, 5, 0, time.UTC),
+		"2012-01-01T12:00:05.12":        time.Date(2012, 1, 1, 12, 0, 5, 120000000, time.UTC),
+		"2012-01-01T12:00:05.12Z":       time.Date(2012, 1, 1, 12, 0, 5, 120000000, time.UTC),
+		"2012-01-01T12:00:05+01:00":     time.Date(2012, 1, 1, 11, 0, 5, 0, time.UTC),
+		"2012-01-01T12:00:05.5-05:30":   time.Date(2012, 1, 1, 17, 30, 5, 500000000, time.UTC),
+		" 2012-01-01T12:00:05Z\n":       time.Date(2012, 1, 1, 12, 0, 5, 0, time.UTC),
+		"":                              {},
+		"0001-01-01T00:00:00":           {},
+		"0001-01-01T00:00:00Z":          {},
+		"0001-01-01T00:00:00.000+00:00": {},
+	}
+
+	for s, expected := range testCases {
+		actual, err := parseTime(s)
+
+		if assert.Nil(t, err, s) {
+			assert.Equal(t, expected, actual, s)
+		}
+	}
+
+	_, err := parseTime("yesterday")
+	assert.NotNil(t, err)
+}
+
+func TestTimeRoundTrip(t *testing.T) {
+	at := time.Date(2012, 1, 1, 12, 0, 5, 120000000, time.FixedZone("", -5*60*60))
+
+	assert := assert.New(t)
+
+	for _, v := range []time.Time{at, {}} {
+		data, _ := messageHeaderTime{v}.MarshalText()
+
+		var m messageHeaderTime
+		if assert.Nil(m.UnmarshalText(data)) {
+			assert.True(v.Equal(m.Time), string(data))
+			assert.Equal(time.UTC, m.Location())
+		}
+	}
+
+	for _, v := range []time.Time{at.Truncate(time.Second), {}} {
+		data, _ := accountsTime{v}.MarshalText()
+
+		var a accountsTime
+		if assert.Nil(a.UnmarshalText(data)) {
+			assert.True(v.Equal(a.Time), string(data))
+			assert.Equal(time.UTC, a.Location())
+		}
+	}
+}
+
+// roundTrip marshals v to XML and unmarshals it into a new value of the same
+// type.
+func roundTrip(t *testing.T, v interface{}, out interface{}) {
+	data, err := xml.Marshal(v)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Nil(t, xml.Unmarshal(data, out), string(data))
+}
+
+func TestTimestampFieldsRoundTrip(t *testing.T) {
+	at := messageHeaderTime{time.Date(2012, 1, 1, 12, 0, 5, 120000000, time.FixedZone("", 2*60*60))}
+	expected := at.UTC()
+
+	assert := assert.New(t)
+
+	var header messageHeadersResponseMessageHeader
+	roundTrip(t, messageHeadersResponseMessageHeader{
+		LastStatusAt: at,
+		SubmittedAt:  at,
+		ReceivedAt:   at,
+		ReadAt:       at,
+		SentAt:       at,
+		DeliveredAt:  at,
+	}, &header)
+	for _, v := range []messageHeaderTime{header.LastStatusAt, header.SubmittedAt, header.ReceivedAt, header.ReadAt, header.SentAt, header.DeliveredAt} {
+		assert.Equal(expected, v.Time)
+	}
+
+	var inbox inboxResponseMessageHeader
+	roundTrip(t, inboxResponseMessageHeader{ReceivedAt: at, ReadAt: at}, &inbox)
+	assert.Equal(expected, inbox.ReceivedAt.Time)
+	assert.Equal(expected, inbox.ReadAt.Time)
+
+	var batch messageBatchResponse
+	roundTrip(t, messageBatchResponse{CreatedAt: at}, &batch)
+	assert.Equal(expected, batch.CreatedAt.Time)
+
+	var optOut optOutsResponseOptOut
+	roundTrip(t, optOutsResponseOptOut{ReceivedAt: at}, &optOut)
+	assert.Equal(expected, optOut.ReceivedAt.Time)
+
+	var delivered messageDeliveredNotification
+	roundTrip(t, messageDeliveredNotification{OccurredAt: at}, &delivered)
+	assert.Equal(expected, delivered.OccurredAt.Time)
+
+	var failed messageFailedNotification
+	roundTrip(t, messageFailedNotification{OccurredAt: at}, &failed)
+	assert.Equal(expected, failed.OccurredAt.Time)
+
+	var account accountsResponseAccount
+	roundTrip(t, accountsResponseAccount{ExpiresOn: accountsTime{at.Truncate(time.Second)}}, &account)
+	assert.Equal(expected.Truncate(time.Second), account.ExpiresOn.Time)
+}
+
+func TestTimestampFieldsWhenAbsent(t *testing.T) {
+	var header messageHeadersResponseMessageHeader
+	roundTrip(t, messageHeadersResponseMessageHeader{}, &header)
+
+	for _, v := range []messageHeaderTime{header.LastStatusAt, header.SubmittedAt, header.ReceivedAt, header.ReadAt, header.SentAt, header.DeliveredAt} {
+		assert.Equal(t, time.Time{}, v.Time)
+	}
+
+	var account accountsResponseAccount
+	roundTrip(t, accountsResponseAccount{}, &account)
+	assert.Equal(t, time.Time{}, account.ExpiresOn.Time)
+}
+
+func TestClientLocation(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<messageheader id="messageid" uri="http://somemessage" xmlns="http://api.esendex.com/ns/">
+ <submittedat>2012-01-01T12:00:05+01:00</submittedat>
+ <sentat>2012-01-01T11:00:06Z</sentat>
+ <deliveredat>2012-01-01T11:00:07</deliveredat>
+ <readat></readat>
+ <laststatusat>0001-01-01T00:00:00</laststatusat>
+</messageheader>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	loc := time.FixedZone("EST", -5*60*60)
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+	client.Location = loc
+
+	message, err := client.Message("messageid")
+
+	assert := assert.New(t)
+
+	if assert.Nil(err) {
+		assert.Equal(time.Date(2012, 1, 1, 6, 0, 5, 0, loc), message.SubmittedAt)
+		assert.Equal(time.Date(2012, 1, 1, 6, 0, 6, 0, loc), message.SentAt)
+		assert.Equal(time.Date(2012, 1, 1, 6, 0, 7, 0, loc), message.DeliveredAt)
+		assert.Equal(time.Time{}, message.ReadAt)
+		assert.Equal(time.Time{}, message.LastStatusAt)
+		assert.Equal(time.Time{}, message.ReceivedAt)
+	}
+}
+
+func TestNotificationHandlerLocation(t *testing.T) {
+	var got []MessageDeliveredNotification
+
+	loc := time.FixedZone("CET", 60*60)
+
+	h := NotificationHandler{
+		Delivered: func(n MessageDeliveredNotification) { got = append(got, n) },
+		Location:  loc,
+	}
+
+	postNotification(h, `<?xml version="1.0" encoding="utf-8"?>
+<MessageDelivered>
+ <Id>notificationid</Id>
+ <OccurredAt>2012-01-01T12:00:05Z</OccurredAt>
+</MessageDelivered>`)
+
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, time.Date(2012, 1, 1, 13, 0, 5, 0, loc), got[0].OccurredAt)
+	}
+}
+
+func TestBetweenUsesUTC(t *testing.T) {
+	h := newRecordingHandler("", 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	loc := time.FixedZone("", 2*60*60)
+	client.Sent(Between(
+		time.Date(2012, 1, 1, 2, 0, 0, 0, loc),
+		time.Date(2012, 1, 2, 2, 0, 0, 0, loc),
+	))
+
+	query := h.Request.URL.Query()
+	assert.Equal(t, "2012-01-01T00:00:00Z", query.Get("start"))
+	assert.Equal(t, "2012-01-02T00:00:00Z", query.Get("finish"))
+}
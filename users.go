@@ -0,0 +1,147 @@
+package esendex
+
+import (
+	"encoding/xml"
+)
+
+// UsersResponse is a list of returned users along with the paging information.
+type UsersResponse struct {
+	Paging
+	Users []UserResponse
+}
+
+// UserResponse is a single user.
+type UserResponse struct {
+	ID           string
+	URI          string
+	Username     string
+	DisplayName  string
+	EmailAddress string
+	AccountsURI  string
+}
+
+// Users returns a list of users that the authenticated user can administer.
+func (c *Client) Users(opts ...Option) (*UsersResponse, error) {
+	req, err := c.newRequest("GET", "/v1.0/users", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyOptions(req, opts); err != nil {
+		return nil, err
+	}
+
+	var v usersResponse
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &UsersResponse{
+		Paging: Paging{
+			StartIndex: v.StartIndex,
+			Count:      v.Count,
+			TotalCount: v.TotalCount,
+		},
+		Users: make([]UserResponse, len(v.Users)),
+	}
+
+	for i, user := range v.Users {
+		response.Users[i] = UserResponse{
+			ID:           user.ID,
+			URI:          user.URI,
+			Username:     user.Username,
+			DisplayName:  user.DisplayName,
+			EmailAddress: user.EmailAddress,
+			AccountsURI:  user.Accounts.URI,
+		}
+	}
+
+	return response, nil
+}
+
+// User returns the user with the given id.
+func (c *Client) User(id string) (*UserResponse, error) {
+	req, err := c.newRequest("GET", "/v1.0/users/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var v usersResponseUser
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &UserResponse{
+		ID:           v.ID,
+		URI:          v.URI,
+		Username:     v.Username,
+		DisplayName:  v.DisplayName,
+		EmailAddress: v.EmailAddress,
+		AccountsURI:  v.Accounts.URI,
+	}
+
+	return response, nil
+}
+
+// UserAccounts returns the accounts that the user with the given id has access
+// to. The Role of each account is the user's role on that account.
+func (c *Client) UserAccounts(id string) (*AccountsResponse, error) {
+	req, err := c.newRequest("GET", "/v1.0/users/"+id+"/accounts", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var v accountsResponse
+	if _, err = c.do(req, &v); err != nil {
+		return nil, err
+	}
+
+	response := &AccountsResponse{
+		Accounts: make([]AccountResponse, len(v.Accounts)),
+	}
+
+	for i, account := range v.Accounts {
+		response.Accounts[i] = AccountResponse{
+			ID:                account.ID,
+			URI:               account.URI,
+			Reference:         account.Reference,
+			Label:             account.Label,
+			Address:           account.Address,
+			Type:              account.Type,
+			MessagesRemaining: account.MessagesRemaining,
+			ExpiresOn:         c.in(account.ExpiresOn.Time),
+			Role:              account.Role,
+			SettingsURI:       account.Settings.URI,
+		}
+	}
+
+	return response, nil
+}
+
+// Users returns a list of users that have access to the account.
+func (c *AccountClient) Users(opts ...Option) (*UsersResponse, error) {
+	accountOption := func(p *Params) error {
+		return p.Set("accountreference", c.reference)
+	}
+
+	return c.Client.Users(append(opts, accountOption)...)
+}
+
+type usersResponse struct {
+	XMLName    xml.Name            `xml:"http://api.esendex.com/ns/ users"`
+	StartIndex int                 `xml:"startindex,attr"`
+	Count      int                 `xml:"count,attr"`
+	TotalCount int                 `xml:"totalcount,attr"`
+	Users      []usersResponseUser `xml:"user"`
+}
+
+type usersResponseUser struct {
+	ID           string `xml:"id,attr"`
+	URI          string `xml:"uri,attr"`
+	Username     string `xml:"username"`
+	DisplayName  string `xml:"displayname"`
+	EmailAddress string `xml:"emailaddress"`
+	Accounts     struct {
+		URI string `xml:"uri,attr"`
+	} `xml:"accounts"`
+}
@@ -0,0 +1,148 @@
+package esendex
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsers(t *testing.T) {
+	const (
+		startIndex   = 0
+		count        = 15
+		totalCount   = 30
+		id           = "userid"
+		uri          = "http://someuser"
+		username     = "jane@example.com"
+		displayName  = "Jane Doe"
+		emailAddress = "jane@example.com"
+		accountsURI  = "http://someuser/accounts"
+	)
+
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<users startindex="`+strconv.Itoa(startIndex)+`" count="`+strconv.Itoa(count)+`" totalcount="`+strconv.Itoa(totalCount)+`" xmlns="http://api.esendex.com/ns/">
+ <user id="`+id+`" uri="`+uri+`">
+  <username>`+username+`</username>
+  <displayname>`+displayName+`</displayname>
+  <emailaddress>`+emailAddress+`</emailaddress>
+  <accounts uri="`+accountsURI+`" />
+ </user>
+</users>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	result, err := client.Users()
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/users", h.Request.URL.String())
+
+	if user, pass, ok := h.Request.BasicAuth(); assert.True(ok) {
+		assert.Equal("user", user)
+		assert.Equal("pass", pass)
+	}
+
+	assert.Equal(startIndex, result.StartIndex)
+	assert.Equal(count, result.Count)
+	assert.Equal(totalCount, result.TotalCount)
+
+	if assert.Equal(1, len(result.Users)) {
+		user := result.Users[0]
+
+		assert.Equal(id, user.ID)
+		assert.Equal(uri, user.URI)
+		assert.Equal(username, user.Username)
+		assert.Equal(displayName, user.DisplayName)
+		assert.Equal(emailAddress, user.EmailAddress)
+		assert.Equal(accountsURI, user.AccountsURI)
+	}
+}
+
+func TestUser(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<user id="userid" uri="http://someuser" xmlns="http://api.esendex.com/ns/">
+ <username>jane@example.com</username>
+ <displayname>Jane Doe</displayname>
+</user>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	user, err := client.User("userid")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/users/userid", h.Request.URL.String())
+
+	assert.Equal("userid", user.ID)
+	assert.Equal("jane@example.com", user.Username)
+	assert.Equal("Jane Doe", user.DisplayName)
+}
+
+func TestUserAccounts(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<accounts xmlns="http://api.esendex.com/ns/">
+ <account id="accountid" uri="http://someaccount">
+  <reference>EX0000000</reference>
+  <role>Administrator</role>
+ </account>
+</accounts>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	result, err := client.UserAccounts("userid")
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/users/userid/accounts", h.Request.URL.String())
+
+	if assert.Equal(1, len(result.Accounts)) {
+		assert.Equal("EX0000000", result.Accounts[0].Reference)
+		assert.Equal("Administrator", result.Accounts[0].Role)
+	}
+}
+
+func TestAccountUsers(t *testing.T) {
+	h := newRecordingHandler(`<?xml version="1.0" encoding="utf-8"?>
+<users startindex="0" count="0" totalcount="0" xmlns="http://api.esendex.com/ns/">
+</users>`, 200, map[string]string{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	client := New("user", "pass")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	_, err := client.Account("EX0000000").Users(Page(10, 5))
+
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Equal("GET", h.Request.Method)
+	assert.Equal("/v1.0/users", h.Request.URL.Path)
+
+	query := h.Request.URL.Query()
+	assert.Equal("EX0000000", query.Get("accountreference"))
+	assert.Equal("10", query.Get("startindex"))
+	assert.Equal("5", query.Get("count"))
+}